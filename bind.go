@@ -0,0 +1,291 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CollectByPrefix gathers the map entries whose keys start with the last
+// segment of prefix and decodes them, sorted by key, into out, which must be a
+// pointer to a slice. The preceding segments select the map to search, so
+// "worker_" searches the top level and "pools.worker_" searches "pools".
+// Keys sort as strings: "worker_10" comes before "worker_2". Elements are
+// decoded with encoding/json, so struct fields follow its rules and tags.
+func (c *ConfigImpl) CollectByPrefix(prefix string, out interface{}) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("config: CollectByPrefix requires a pointer to a slice, got %T", out)
+	}
+	parts := c.split(prefix)
+	keyPrefix := parts[len(parts)-1]
+	parent := c.join(parts[:len(parts)-1]...)
+	m, err := c.Map(parent)
+	if err != nil {
+		return err
+	}
+	var keys []string
+	for k := range m {
+		if strings.HasPrefix(k, keyPrefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	items := make([]interface{}, len(keys))
+	for i, k := range keys {
+		items[i] = m[k]
+	}
+	data, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("config: decoding %q entries: %w", prefix, err)
+	}
+	return nil
+}
+
+// Bind populates the struct out points to from the config. As in
+// ValidateSchema, a field tagged `config:"path"` reads the path relative to
+// its parent struct, and untagged struct fields are bound at their parent's
+// level. Nested structs read objects, slices read lists (of structs too),
+// maps with string keys read objects, pointers are allocated as needed and
+// time.Duration reads a duration string or a number of nanoseconds. When the
+// path is absent, a `default:"..."` tag is parsed into the field (a comma
+// separated list for slices); without one the field is left untouched. A
+// value of the wrong type is an error naming its path.
+func (c *ConfigImpl) Bind(out interface{}) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("config: Bind requires a pointer to a struct, got %T", out)
+	}
+	return c.bindStruct(c.tree(), rv.Elem(), nil)
+}
+
+func (c *ConfigImpl) bindStruct(node interface{}, v reflect.Value, prefix []string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			continue
+		}
+		fv := v.Field(i)
+		tag, tagged := f.Tag.Lookup("config")
+		if !tagged {
+			if f.Type.Kind() == reflect.Struct && f.Type != durationType {
+				if err := c.bindStruct(node, fv, prefix); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if tag == "" || tag == "-" {
+			continue
+		}
+		rel := c.split(tag)
+		path := c.join(append(append([]string{}, prefix...), rel...)...)
+		x, err := fetchWith(node, rel, c.opts)
+		if err != nil {
+			if !errors.Is(err, ErrNotFound) {
+				return err
+			}
+			if def, ok := f.Tag.Lookup("default"); ok {
+				if err := bindDefault(fv, def, path); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if err := c.bindValue(fv, x, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *ConfigImpl) bindValue(v reflect.Value, x interface{}, path string) error {
+	if v.Kind() == reflect.Ptr {
+		if x == nil {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		elem := reflect.New(v.Type().Elem())
+		if err := c.bindValue(elem.Elem(), x, path); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+	if v.Type() == durationType {
+		if s, ok := x.(string); ok {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return newPathError(ErrWrongType, "config: Invalid duration %q at %q", s, path)
+			}
+			v.SetInt(int64(d))
+			return nil
+		}
+		if n, ok := toInt64(x); ok {
+			v.SetInt(n)
+			return nil
+		}
+		return wrongType(x, path)
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		if b, ok := x.(bool); ok {
+			v.SetBool(b)
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, ok := toInt64(x); ok && !v.OverflowInt(n) {
+			v.SetInt(n)
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, ok := toUint64(x); ok && !v.OverflowUint(n) {
+			v.SetUint(n)
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		if f, ok := toFloat64(x); ok {
+			v.SetFloat(f)
+			return nil
+		}
+	case reflect.String:
+		if s, ok := x.(string); ok {
+			v.SetString(s)
+			return nil
+		}
+		if c.opts.stringCoercion {
+			if s, ok := coerceString(x); ok {
+				v.SetString(s)
+				return nil
+			}
+		}
+	case reflect.Struct:
+		if m, ok := x.(map[string]interface{}); ok {
+			return c.bindStruct(m, v, c.split(path))
+		}
+	case reflect.Slice:
+		if list, ok := x.([]interface{}); ok {
+			out := reflect.MakeSlice(v.Type(), len(list), len(list))
+			for i, e := range list {
+				if err := c.bindValue(out.Index(i), e, joinPath(path, strconv.Itoa(i), c.separator())); err != nil {
+					return err
+				}
+			}
+			v.Set(out)
+			return nil
+		}
+	case reflect.Map:
+		m, ok := x.(map[string]interface{})
+		if ok && v.Type().Key().Kind() == reflect.String {
+			out := reflect.MakeMapWithSize(v.Type(), len(m))
+			for k, e := range m {
+				elem := reflect.New(v.Type().Elem()).Elem()
+				if err := c.bindValue(elem, e, joinPath(path, k, c.separator())); err != nil {
+					return err
+				}
+				out.SetMapIndex(reflect.ValueOf(k).Convert(v.Type().Key()), elem)
+			}
+			v.Set(out)
+			return nil
+		}
+	case reflect.Interface:
+		if val := reflect.ValueOf(copyValue(x)); x != nil && val.Type().AssignableTo(v.Type()) {
+			v.Set(val)
+			return nil
+		}
+	}
+	return wrongType(x, path)
+}
+
+// bindDefault parses the default tag s into v.
+func bindDefault(v reflect.Value, s, path string) error {
+	if v.Kind() == reflect.Ptr {
+		elem := reflect.New(v.Type().Elem())
+		if err := bindDefault(elem.Elem(), s, path); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+	invalid := newPathError(ErrInvalidValue, "config: Invalid default %q for %q", s, path)
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return invalid
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return invalid
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return invalid
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return invalid
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return invalid
+		}
+		v.SetFloat(f)
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Slice:
+		var items []string
+		if s != "" {
+			items = strings.Split(s, ",")
+		}
+		out := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := bindDefault(out.Index(i), strings.TrimSpace(item), path); err != nil {
+				return err
+			}
+		}
+		v.Set(out)
+	default:
+		return invalid
+	}
+	return nil
+}
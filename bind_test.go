@@ -0,0 +1,123 @@
+package config_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigCollectByPrefix(t *testing.T) {
+	cfg, err := config.ParseJSON(`{
+		"worker_2": {"name": "beta", "threads": 2},
+		"worker_1": {"name": "alpha", "threads": 4},
+		"workers": "not a worker section",
+		"pools": {"worker_a": {"name": "pooled", "threads": 1}, "other": {}}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type Worker struct {
+		Name    string `json:"name"`
+		Threads int    `json:"threads"`
+	}
+	var workers []Worker
+	assert.NoError(t, cfg.CollectByPrefix("worker_", &workers))
+	assert.Equal(t, []Worker{{"alpha", 4}, {"beta", 2}}, workers)
+
+	var pooled []Worker
+	assert.NoError(t, cfg.CollectByPrefix("pools.worker_", &pooled))
+	assert.Equal(t, []Worker{{"pooled", 1}}, pooled)
+
+	assert.Error(t, cfg.CollectByPrefix("worker_", workers))
+
+	var bad []Worker
+	assert.Error(t, cfg.CollectByPrefix("work", &bad))
+}
+
+func Test_ConfigBind(t *testing.T) {
+	cfg, err := config.ParseJSON(`{
+		"server": {"host": "example.com", "port": 8080, "timeout": "5s", "ratio": 0.5},
+		"debug": true,
+		"tags": ["a", "b"],
+		"limits": {"read": 10, "write": 20},
+		"backends": [{"host": "b1", "weight": 3}, {"host": "b2"}],
+		"log": {"level": "warn"}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type Backend struct {
+		Host   string `config:"host"`
+		Weight int    `config:"weight" default:"1"`
+	}
+	type Logging struct {
+		Level string `config:"log.level"`
+	}
+	type Settings struct {
+		Host     string         `config:"server.host"`
+		Port     int            `config:"server.port"`
+		Timeout  time.Duration  `config:"server.timeout"`
+		Ratio    float32        `config:"server.ratio"`
+		Debug    *bool          `config:"debug"`
+		Tags     []string       `config:"tags"`
+		Limits   map[string]int `config:"limits"`
+		Backends []Backend      `config:"backends"`
+		Retries  int            `config:"retries" default:"3"`
+		Zones    []string       `config:"zones" default:"eu, us"`
+		Proxy    *string        `config:"proxy"`
+		Kept     string         `config:"missing"`
+		Logging
+	}
+	s := Settings{Kept: "unchanged"}
+	assert.NoError(t, cfg.Bind(&s))
+
+	assert.Equal(t, "example.com", s.Host)
+	assert.Equal(t, 8080, s.Port)
+	assert.Equal(t, 5*time.Second, s.Timeout)
+	assert.Equal(t, float32(0.5), s.Ratio)
+	if assert.NotNil(t, s.Debug) {
+		assert.True(t, *s.Debug)
+	}
+	assert.Equal(t, []string{"a", "b"}, s.Tags)
+	assert.Equal(t, map[string]int{"read": 10, "write": 20}, s.Limits)
+	assert.Equal(t, []Backend{{"b1", 3}, {"b2", 1}}, s.Backends)
+	assert.Equal(t, 3, s.Retries)
+	assert.Equal(t, []string{"eu", "us"}, s.Zones)
+	assert.Nil(t, s.Proxy)
+	assert.Equal(t, "unchanged", s.Kept)
+	assert.Equal(t, "warn", s.Level)
+
+	var wrong struct {
+		Port string `config:"server.port"`
+	}
+	err = cfg.Bind(&wrong)
+	assert.True(t, errors.Is(err, config.ErrWrongType))
+	assert.Contains(t, err.Error(), `"server.port"`)
+
+	var quotas struct {
+		Quotas map[string]int `config:"quotas"`
+	}
+	dotted, err := config.ParseJSON(`{"quotas": {"api.v1": "many"}}`)
+	assert.NoError(t, err)
+	err = dotted.Bind(&quotas)
+	var pathErr *config.PathError
+	assert.True(t, errors.As(err, &pathErr))
+	assert.Equal(t, `quotas.api\.v1`, pathErr.FullPath)
+
+	var nested struct {
+		Weight int `config:"backends.1.weight"`
+	}
+	assert.NoError(t, cfg.Bind(&nested))
+	assert.Equal(t, 0, nested.Weight)
+
+	var badDefault struct {
+		N int `config:"n" default:"many"`
+	}
+	assert.True(t, errors.Is(cfg.Bind(&badDefault), config.ErrInvalidValue))
+	assert.Error(t, cfg.Bind(s))
+}
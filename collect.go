@@ -0,0 +1,174 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Getter reads values from a config inside Collect. Each getter returns the
+// value on success and the zero value on failure, recording the error instead
+// of returning it.
+type Getter struct {
+	cfg  Config
+	errs []error
+}
+
+// CollectError is returned by Collect when any getter failed. Errors holds
+// the failures in the order they happened; errors.Is and errors.As match
+// any of them.
+type CollectError struct {
+	Errors []error
+}
+
+func (e *CollectError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("config: %d values could not be read:\n  %s", len(e.Errors), strings.Join(msgs, "\n  "))
+}
+
+func (e *CollectError) Unwrap() []error {
+	return e.Errors
+}
+
+// Collect calls fn with a Getter on the config and returns a *CollectError
+// listing every read that failed, or nil if all succeeded. It lets startup
+// code read a batch of settings and check them once:
+//
+//	err := cfg.Collect(func(g *config.Getter) {
+//		host = g.String("host")
+//		port = g.Int("port")
+//	})
+func (c *ConfigImpl) Collect(fn func(g *Getter)) error {
+	g := &Getter{cfg: c}
+	fn(g)
+	if len(g.errs) == 0 {
+		return nil
+	}
+	return &CollectError{Errors: g.errs}
+}
+
+// failed records err, if any, and reports whether there was one.
+func (g *Getter) failed(err error) bool {
+	if err != nil {
+		g.errs = append(g.errs, err)
+	}
+	return err != nil
+}
+
+// String records the error of Config.String.
+func (g *Getter) String(path string) string {
+	s, err := g.cfg.String(path)
+	if g.failed(err) {
+		return ""
+	}
+	return s
+}
+
+// Bool records the error of Config.Bool.
+func (g *Getter) Bool(path string) bool {
+	b, err := g.cfg.Bool(path)
+	if g.failed(err) {
+		return false
+	}
+	return b
+}
+
+// Int records the error of Config.Int.
+func (g *Getter) Int(path string) int {
+	i, err := g.cfg.Int(path)
+	if g.failed(err) {
+		return 0
+	}
+	return i
+}
+
+// Int64 records the error of Config.Int64.
+func (g *Getter) Int64(path string) int64 {
+	i, err := g.cfg.Int64(path)
+	if g.failed(err) {
+		return 0
+	}
+	return i
+}
+
+// Uint64 records the error of Config.Uint64.
+func (g *Getter) Uint64(path string) uint64 {
+	u, err := g.cfg.Uint64(path)
+	if g.failed(err) {
+		return 0
+	}
+	return u
+}
+
+// Float records the error of Config.Float.
+func (g *Getter) Float(path string) float64 {
+	f, err := g.cfg.Float(path)
+	if g.failed(err) {
+		return 0
+	}
+	return f
+}
+
+// Map records the error of Config.Map.
+func (g *Getter) Map(path string) map[string]interface{} {
+	m, err := g.cfg.Map(path)
+	if g.failed(err) {
+		return nil
+	}
+	return m
+}
+
+// List records the error of Config.List.
+func (g *Getter) List(path string) []interface{} {
+	l, err := g.cfg.List(path)
+	if g.failed(err) {
+		return nil
+	}
+	return l
+}
+
+// Bytes records the error of Config.Bytes.
+func (g *Getter) Bytes(path string) int64 {
+	n, err := g.cfg.Bytes(path)
+	if g.failed(err) {
+		return 0
+	}
+	return n
+}
+
+// URL records the error of Config.URL.
+func (g *Getter) URL(path string) *url.URL {
+	u, err := g.cfg.URL(path)
+	if g.failed(err) {
+		return nil
+	}
+	return u
+}
+
+// Require records the error of Config.Require.
+func (g *Getter) Require(paths ...string) {
+	g.failed(g.cfg.Require(paths...))
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SetComment attaches a comment to the dotted path. Comments are kept beside
+// the data and emitted by ToJSONC; an empty comment removes it.
+func (c *ConfigImpl) SetComment(path, comment string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if comment == "" {
+		delete(c.comments, path)
+		return
+	}
+	if c.comments == nil {
+		c.comments = map[string]string{}
+	}
+	c.comments[path] = comment
+}
+
+// Comment returns the comment attached to the dotted path, if any.
+func (c *ConfigImpl) Comment(path string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.comments[path]
+}
+
+// Annotate attaches a description to the path, typically documenting the key
+// from code. Annotations are the comments of SetComment under another name:
+// they live beside the data and are carried over by Clone and Merge. Only
+// Annotation, Annotations and ToJSONC, which writes them next to the values
+// they describe, show them; Dump, Render, Raw and MarshalJSON produce plain
+// JSON and leave them out. An empty description removes the annotation.
+func (c *ConfigImpl) Annotate(path, description string) {
+	c.SetComment(path, description)
+}
+
+// Annotation returns the description attached to the path and whether there
+// is one.
+func (c *ConfigImpl) Annotation(path string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	d, ok := c.comments[path]
+	return d, ok
+}
+
+// Annotations returns a copy of every annotation keyed by path.
+func (c *ConfigImpl) Annotations() map[string]string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := make(map[string]string, len(c.comments))
+	for path, d := range c.comments {
+		out[path] = d
+	}
+	return out
+}
+
+// ToJSONC renders the config as indented JSON with sorted keys, preceding
+// each commented path with its comment as "//" lines. A comment on the empty
+// path is written at the top of the document.
+func (c *ConfigImpl) ToJSONC() ([]byte, error) {
+	root := c.tree()
+	c.mu.RLock()
+	comments := make(map[string]string, len(c.comments))
+	for k, v := range c.comments {
+		comments[k] = v
+	}
+	c.mu.RUnlock()
+
+	w := &jsoncWriter{comments: comments, sep: c.separator()}
+	w.comment("", "")
+	if err := w.value(root, "", ""); err != nil {
+		return nil, err
+	}
+	w.buf.WriteString("\n")
+	return w.buf.Bytes(), nil
+}
+
+type jsoncWriter struct {
+	buf      bytes.Buffer
+	comments map[string]string
+	sep      string
+}
+
+func (w *jsoncWriter) comment(path, indent string) {
+	text, ok := w.comments[path]
+	if !ok {
+		return
+	}
+	for _, line := range strings.Split(text, "\n") {
+		w.buf.WriteString(indent + "// " + line + "\n")
+	}
+}
+
+func (w *jsoncWriter) value(v interface{}, path, indent string) error {
+	inner := indent + "    "
+	switch x := v.(type) {
+	case map[string]interface{}:
+		if len(x) == 0 {
+			w.buf.WriteString("{}")
+			return nil
+		}
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		w.buf.WriteString("{\n")
+		for i, k := range keys {
+			child := joinPath(path, k, w.sep)
+			w.comment(child, inner)
+			key, _ := json.Marshal(k)
+			w.buf.WriteString(inner)
+			w.buf.Write(key)
+			w.buf.WriteString(": ")
+			if err := w.value(x[k], child, inner); err != nil {
+				return err
+			}
+			if i < len(keys)-1 {
+				w.buf.WriteString(",")
+			}
+			w.buf.WriteString("\n")
+		}
+		w.buf.WriteString(indent + "}")
+	case []interface{}:
+		if len(x) == 0 {
+			w.buf.WriteString("[]")
+			return nil
+		}
+		w.buf.WriteString("[\n")
+		for i, e := range x {
+			child := joinPath(path, strconv.Itoa(i), w.sep)
+			w.comment(child, inner)
+			w.buf.WriteString(inner)
+			if err := w.value(e, child, inner); err != nil {
+				return err
+			}
+			if i < len(x)-1 {
+				w.buf.WriteString(",")
+			}
+			w.buf.WriteString("\n")
+		}
+		w.buf.WriteString(indent + "]")
+	default:
+		b, err := json.Marshal(x)
+		if err != nil {
+			return err
+		}
+		w.buf.Write(b)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package config_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigComment(t *testing.T) {
+	cfg, err := config.ParseJSON(`{"server": {"port": 8080, "hosts": ["a", "b"]}, "debug": false}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.SetComment("server.port", "Port the HTTP listener binds to.")
+	cfg.SetComment("server.hosts.1", "Fallback host")
+	assert.Equal(t, "Port the HTTP listener binds to.", cfg.Comment("server.port"))
+	assert.Equal(t, "", cfg.Comment("debug"))
+
+	out, err := cfg.ToJSONC()
+	assert.NoError(t, err)
+	assert.Equal(t, `{
+    "debug": false,
+    "server": {
+        "hosts": [
+            "a",
+            // Fallback host
+            "b"
+        ],
+        // Port the HTTP listener binds to.
+        "port": 8080
+    }
+}
+`, string(out))
+}
+
+func Test_ConfigAnnotate(t *testing.T) {
+	cfg, err := config.ParseJSON(`{"server": {"port": 8080}, "debug": false}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Annotate("server.port", "Port the HTTP listener binds to.")
+	cfg.Annotate("debug", "Enables verbose logging.")
+
+	d, ok := cfg.Annotation("server.port")
+	assert.True(t, ok)
+	assert.Equal(t, "Port the HTTP listener binds to.", d)
+	_, ok = cfg.Annotation("server")
+	assert.False(t, ok)
+
+	clone := cfg.Clone()
+	assert.Equal(t, map[string]string{
+		"server.port": "Port the HTTP listener binds to.",
+		"debug":       "Enables verbose logging.",
+	}, clone.Annotations())
+	assert.NoError(t, clone.Set("server.port", 9090))
+	assert.Equal(t, 8080, cfg.MustInt("server.port"))
+
+	out, err := json.Marshal(cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"debug":false,"server":{"port":8080}}`, string(out))
+	assert.NotContains(t, cfg.Dump(), "HTTP listener")
+	jsonc, err := cfg.ToJSONC()
+	assert.NoError(t, err)
+	assert.Contains(t, string(jsonc), "// Port the HTTP listener binds to.")
+
+	cfg.Annotate("debug", "")
+	_, ok = cfg.Annotation("debug")
+	assert.False(t, ok)
+	_, ok = clone.Annotation("debug")
+	assert.True(t, ok)
+}
@@ -21,11 +21,19 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
-	"fmt"
+	"errors"
+	"flag"
+	"io"
 	"io/ioutil"
+	"net/url"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 type (
@@ -34,38 +42,256 @@ type (
 	Config interface {
 		String(string) (string, error)
 		Bool(string) (bool, error)
+		BoolCoerce(string) (bool, error)
+		IntStrict(string) (int, error)
+		IntTrunc(string) (int, error)
+		StringOr(string, string) string
+		StringOrFunc(string, func() string) string
+		IntOr(string, int) int
+		BoolOr(string, bool) bool
+		FloatOr(string, float64) float64
+		IntRound(string) (int, error)
+		FirstOf(...string) (interface{}, error)
+		StringFirstOf(...string) (string, error)
+		IntFirstOf(...string) (int, error)
+		BoolFirstOf(...string) (bool, error)
+		FloatFirstOf(...string) (float64, error)
+		RegisterDeprecated(string, string)
+		CheckDeprecated() []string
 		Int(string) (int, error)
+		Int64(string) (int64, error)
+		Uint64(string) (uint64, error)
 		Float(string) (float64, error)
 		Map(string) (map[string]interface{}, error)
+		MapMerged(string, map[string]interface{}) (map[string]interface{}, error)
+		IntMap(string) (map[string]int, error)
+		StringMap(string) (map[string]string, error)
+		FloatMap(string) (map[string]float64, error)
+		BoolMap(string) (map[string]bool, error)
 		List(string) ([]interface{}, error)
+		NormalizeToList(string) ([]interface{}, error)
+		MapList(string) ([]map[string]interface{}, error)
+		FilterListByEnv(path, env string) ([]interface{}, error)
+		Rate(string) (float64, error)
+		Percent(string) (float64, error)
+		Bytes(string) (int64, error)
+		Duration(string) (time.Duration, error)
+		DurationList(string) ([]time.Duration, error)
+		DurationMap(string) (map[string]time.Duration, error)
+		URL(string) (*url.URL, error)
+		Regexp(string) (*regexp.Regexp, error)
+		Base64(string) ([]byte, error)
+		Has(string) bool
+		IsNull(string) (bool, error)
+		TypeOf(string) (string, error)
+		Query(string) ([]interface{}, error)
+		QueryStrict(string) ([]interface{}, error)
+		Keys(string) ([]string, error)
+		Walk(func(path string, value interface{}) error) error
+		WalkTree(func(path string, value interface{}) error) error
+		Require(...string) error
+		Collect(func(*Getter)) error
+		ApplyDefaults() error
+		ValidateJSONSchema([]byte) error
+		CheckPaths([]string) map[string]bool
+		RenderTemplates() error
+		ResolveReferences() error
+		ValidateSchema(interface{}) error
+		ValidateInt(path string, min, max int) error
+		ValidateFloat(path string, min, max float64) error
+		ValidateEnum(path string, allowed ...string) error
+		EachConfig(string, func(int, Config) error) error
+		SubList(string) ([]Config, error)
+		MustSubList(string) []Config
+		SubListResolved(path, extendsKey, nameKey string) ([]Config, error)
+		CollectByPrefix(string, interface{}) error
+		Bind(interface{}) error
+		Flatten() map[string]interface{}
+		Paths() []string
+		PathsUnder(string) []string
+		Raw() map[string]interface{}
+		RawCopy() map[string]interface{}
+		EffectiveConfig() map[string]ValueInfo
+		Dump(redact ...string) string
+		Render() string
 
 		MustString(string, ...string) string
 		MustBool(string, ...bool) bool
 		MustInt(string, ...int) int
+		MustInt64(string, ...int64) int64
+		MustUint64(string, ...uint64) uint64
 		MustFloat(string, ...float64) float64
 		MustMap(string, ...map[string]interface{}) map[string]interface{}
 		MustList(string, ...[]interface{}) []interface{}
+		MustMapList(string, ...[]map[string]interface{}) []map[string]interface{}
+		MustIntMap(string, ...map[string]int) map[string]int
+		MustStringMap(string, ...map[string]string) map[string]string
+		MustFloatMap(string, ...map[string]float64) map[string]float64
+		MustBoolMap(string, ...map[string]bool) map[string]bool
+		MustRate(string, ...float64) float64
+		MustPercent(string, ...float64) float64
+		MustBytes(string, ...int64) int64
+		MustDuration(string, ...time.Duration) time.Duration
+		MustURL(string, ...*url.URL) *url.URL
+		MustRegexp(string, ...*regexp.Regexp) *regexp.Regexp
+		MustBase64(string, ...[]byte) []byte
+
+		MustStringStrict(string, ...string) string
+		MustBoolStrict(string, ...bool) bool
+		MustIntStrict(string, ...int) int
+		MustInt64Strict(string, ...int64) int64
+		MustUint64Strict(string, ...uint64) uint64
+		MustFloatStrict(string, ...float64) float64
+		MustMapStrict(string, ...map[string]interface{}) map[string]interface{}
+		MustListStrict(string, ...[]interface{}) []interface{}
+
+		StringPtr(string) *string
+		IntPtr(string) *int
+		BoolPtr(string) *bool
+		FloatPtr(string) *float64
 
 		Extend(Config) (Config, error)
+		Merge(...Config) (Config, error)
+		MergeWith(MergeOption, ...Config) (Config, error)
+		MergeReport(...Config) (Config, []Change, error)
+		Patch(Config) (map[string]interface{}, error)
+		ApplyPatch(map[string]interface{}) error
+		Set(string, interface{}) error
+		Delete(string) error
+		Append(string, ...interface{}) error
+		Snapshot() func()
+		Transaction(func(Config) error) error
+		Freeze() Config
+		GetOrInit(string, func(raw interface{}) (interface{}, error)) (interface{}, error)
+		Seal(string)
+		OverrideFromEnv(string) error
+		ToEnv(string) []string
+		BindFlags(*flag.FlagSet, map[string]string) error
+
+		ReloadFile(string) error
+		OnChange(string, func(old, new interface{}))
+		OnReloadError(func(error))
+		ValidateReload(func(Config) error)
+		WatchString(string, func(string))
+		WatchInt(string, func(int))
+		WatchBool(string, func(bool))
+
+		SetComment(string, string)
+		Comment(string) string
+		Annotate(string, string)
+		Annotation(string) (string, bool)
+		Annotations() map[string]string
+		Clone() Config
+		ToJSONC() ([]byte, error)
 	}
 
 	//ConfigImpl struct to hold configuration data
 	ConfigImpl struct {
-		root map[string]interface{}
+		mu         sync.RWMutex
+		writeMu    sync.Mutex
+		notices    []func()
+		opts       options
+		root       map[string]interface{}
+		watchers   map[string][]func(old, new interface{})
+		validators []func(Config) error
+		reloadErrs []func(error)
+		sealed     []string
+		comments   map[string]string
+		origin     string
+		sources    map[string]string
+		memo       map[string]interface{}
+		gen        uint64
+		deprecated []deprecation
+		pending    map[string]json.RawMessage
 	}
 )
 
-// Get returns a value for the dotted path.
+// Get returns a value for the dotted path, falling back to the global
+// default registered with SetGlobalDefault when the path is absent.
 func (c *ConfigImpl) Get(path string) (interface{}, error) {
-	return fetchValue(c.root, path)
+	x, err := c.lookup(path)
+	if errors.Is(err, ErrNotFound) {
+		if def, ok := globalDefault(c.split(path)); ok {
+			return def, nil
+		}
+	}
+	return x, err
+}
+
+// Has reports whether the path resolves to a value in this config. A present
+// value counts even if it is false, zero, empty or null; global defaults do
+// not count.
+func (c *ConfigImpl) Has(path string) bool {
+	_, err := c.lookup(path)
+	return err == nil
+}
+
+// IsNull reports whether the value at the path is null. It fails only when
+// the path does not resolve. The typed getters report a null value as
+// ErrWrongType with a "Null value" message; Map and List in particular never
+// return a nil result without an error.
+func (c *ConfigImpl) IsNull(path string) (bool, error) {
+	x, err := c.Get(path)
+	if err != nil {
+		return false, err
+	}
+	return x == nil, nil
+}
+
+// lookup resolves the path in this config only.
+func (c *ConfigImpl) lookup(path string) (interface{}, error) {
+	parts := c.split(path)
+	return fetchWith(c.section(parts), parts, c.opts)
+}
+
+// useDefault reports whether a Must getter should return its own default
+// rather than a resolved value: instance defaults win over global defaults.
+func (c *ConfigImpl) useDefault(path string, defaults int) bool {
+	return defaults > 0 && !c.Has(path)
+}
+
+// tree returns the current root. Reloads swap the root rather than
+// mutating it, so the returned map stays consistent for the caller.
+func (c *ConfigImpl) tree() map[string]interface{} {
+	c.mu.RLock()
+	root, lazy := c.root, len(c.pending) > 0
+	c.mu.RUnlock()
+	if lazy {
+		return c.decodePending(nil)
+	}
+	return root
+}
+
+//Raw returns the root map itself. It aliases the config's data: callers must
+//not modify it, and should use RawCopy when they need to.
+func (c *ConfigImpl) Raw() map[string]interface{} {
+	return c.tree()
+}
+
+//RawCopy returns a deep copy of the root map that the caller may modify.
+func (c *ConfigImpl) RawCopy() map[string]interface{} {
+	return copyValue(c.tree()).(map[string]interface{})
 }
 
-//Extend shallow merge the with other config data
+//Extend shallow merges the other config data into this one IN PLACE: top-level
+//keys of cfg replace those of the receiver, which is modified and returned.
+//Use Merge to build a new config and leave every input untouched.
 func (c *ConfigImpl) Extend(cfg Config) (Config, error) {
 	if cfg != nil {
-		for k, v := range cfg.(*ConfigImpl).root {
-			c.root[k] = v
+		defer c.beginWrite()()
+		other := cfg.Raw()
+		root := c.tree()
+		next := make(map[string]interface{}, len(root)+len(other))
+		for k, v := range root {
+			next[k] = v
 		}
+		for k, v := range other {
+			next[k] = v
+		}
+		if err := c.commit(next); err != nil {
+			return nil, err
+		}
+		c.noteSources(originsOf(cfg))
 	}
 	return c, nil
 }
@@ -80,12 +306,17 @@ func (c *ConfigImpl) String(path string) (string, error) {
 	case string:
 		return x.(string), nil
 	}
-	return "", fmt.Errorf("config: Unknown type at %q", path)
+	if c.opts.stringCoercion {
+		if s, ok := coerceString(x); ok {
+			return s, nil
+		}
+	}
+	return "", wrongType(x, path)
 }
 
 func (c *ConfigImpl) MustString(path string, defaults ...string) string {
 	s, err := c.String(path)
-	if err == nil {
+	if err == nil && !c.useDefault(path, len(defaults)) {
 		return s
 	}
 	for _, v := range defaults {
@@ -104,12 +335,12 @@ func (c *ConfigImpl) Bool(path string) (bool, error) {
 	case bool:
 		return x.(bool), nil
 	}
-	return false, fmt.Errorf("config: Unknown type at %q", path)
+	return false, wrongType(x, path)
 }
 
 func (c *ConfigImpl) MustBool(path string, defaults ...bool) bool {
 	b, err := c.Bool(path)
-	if err == nil {
+	if err == nil && !c.useDefault(path, len(defaults)) {
 		return b
 	}
 	for _, v := range defaults {
@@ -118,52 +349,101 @@ func (c *ConfigImpl) MustBool(path string, defaults ...bool) bool {
 	return false
 }
 
-//Int returns the int value for the dotted path
+//Int returns the int value for the dotted path. Whole-number floats and Go
+//integer values that fit in an int are accepted; a fractional part is an error.
 func (c *ConfigImpl) Int(path string) (int, error) {
 	x, err := c.Get(path)
 	if err != nil {
 		return -1, err
 	}
-	switch x.(type) {
-	case float64:
-		return int(x.(float64)), nil
+	if i, ok := toInt64(x); ok && int64(int(i)) == i {
+		return int(i), nil
 	}
-	return -1, fmt.Errorf("config: Unknown type at %q", path)
+	return -1, wrongType(x, path)
 }
 
 func (c *ConfigImpl) MustInt(path string, defaults ...int) int {
 	i, err := c.Int(path)
-	if err == nil {
+	if err == nil && !c.useDefault(path, len(defaults)) {
 		return i
 	}
 	for _, v := range defaults {
 		return v
 	}
-	return -1
+	return int(missingNumber())
+}
+
+// Int64 returns the int64 value for the dotted path. JSON numbers are decoded
+// as float64, which represents integers exactly only up to 2^53; larger
+// values may already have lost precision when the config was parsed.
+func (c *ConfigImpl) Int64(path string) (int64, error) {
+	x, err := c.Get(path)
+	if err != nil {
+		return -1, err
+	}
+	if i, ok := toInt64(x); ok {
+		return i, nil
+	}
+	return -1, wrongType(x, path)
+}
+
+func (c *ConfigImpl) MustInt64(path string, defaults ...int64) int64 {
+	i, err := c.Int64(path)
+	if err == nil && !c.useDefault(path, len(defaults)) {
+		return i
+	}
+	for _, v := range defaults {
+		return v
+	}
+	return missingNumber()
+}
+
+// Uint64 returns the uint64 value for the dotted path. Negative values and
+// values beyond the uint64 range are reported as ErrWrongType. The float64
+// precision caveat of Int64 applies.
+func (c *ConfigImpl) Uint64(path string) (uint64, error) {
+	x, err := c.Get(path)
+	if err != nil {
+		return 0, err
+	}
+	if u, ok := toUint64(x); ok {
+		return u, nil
+	}
+	return 0, wrongType(x, path)
+}
+
+func (c *ConfigImpl) MustUint64(path string, defaults ...uint64) uint64 {
+	u, err := c.Uint64(path)
+	if err == nil && !c.useDefault(path, len(defaults)) {
+		return u
+	}
+	for _, v := range defaults {
+		return v
+	}
+	return 0
 }
 
-//Float returns the float value for the dotted path
+//Float returns the float value for the dotted path, widening Go integer values.
 func (c *ConfigImpl) Float(path string) (float64, error) {
 	x, err := c.Get(path)
 	if err != nil {
 		return -1, err
 	}
-	switch x.(type) {
-	case float64:
-		return x.(float64), nil
+	if f, ok := toFloat64(x); ok {
+		return f, nil
 	}
-	return -1, fmt.Errorf("config: Unknown type at %q", path)
+	return -1, wrongType(x, path)
 }
 
 func (c *ConfigImpl) MustFloat(path string, defaults ...float64) float64 {
 	i, err := c.Float(path)
-	if err == nil {
+	if err == nil && !c.useDefault(path, len(defaults)) {
 		return i
 	}
 	for _, def := range defaults {
 		return def
 	}
-	return -1
+	return float64(missingNumber())
 }
 
 func (c *ConfigImpl) Map(path string) (map[string]interface{}, error) {
@@ -175,12 +455,12 @@ func (c *ConfigImpl) Map(path string) (map[string]interface{}, error) {
 	case map[string]interface{}:
 		return x.(map[string]interface{}), nil
 	}
-	return nil, fmt.Errorf("config: Unknown type at %q", path)
+	return nil, wrongType(x, path)
 }
 
 func (c *ConfigImpl) MustMap(path string, defaults ...map[string]interface{}) map[string]interface{} {
 	val, err := c.Map(path)
-	if err == nil {
+	if err == nil && !c.useDefault(path, len(defaults)) {
 		return val
 	}
 	for _, def := range defaults {
@@ -189,6 +469,23 @@ func (c *ConfigImpl) MustMap(path string, defaults ...map[string]interface{}) ma
 	return map[string]interface{}{}
 }
 
+// MapMerged returns the map at the dotted path deep-merged over a copy of
+// defaults, so keys missing from the config fall back to their defaults. An
+// absent path yields the defaults alone.
+func (c *ConfigImpl) MapMerged(path string, defaults map[string]interface{}) (map[string]interface{}, error) {
+	out := map[string]interface{}{}
+	mergeDeep(out, defaults)
+	val, err := c.Map(path)
+	if errors.Is(err, ErrNotFound) {
+		return out, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	mergeDeep(out, val)
+	return out, nil
+}
+
 func (c *ConfigImpl) List(path string) ([]interface{}, error) {
 	x, err := c.Get(path)
 	if err != nil {
@@ -198,12 +495,12 @@ func (c *ConfigImpl) List(path string) ([]interface{}, error) {
 	case []interface{}:
 		return x.([]interface{}), nil
 	}
-	return nil, fmt.Errorf("config: Unknown type at %q", path)
+	return nil, wrongType(x, path)
 }
 
 func (c *ConfigImpl) MustList(path string, defaults ...[]interface{}) []interface{} {
 	val, err := c.List(path)
-	if err == nil {
+	if err == nil && !c.useDefault(path, len(defaults)) {
 		return val
 	}
 	for _, def := range defaults {
@@ -214,32 +511,82 @@ func (c *ConfigImpl) MustList(path string, defaults ...[]interface{}) []interfac
 
 //Fetch
 
+// foldKey finds the value whose key matches part under case folding.
+func foldKey(m map[string]interface{}, part, fullPath, curPath string) (interface{}, error) {
+	var found []string
+	for k := range m {
+		if strings.EqualFold(k, part) {
+			found = append(found, k)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, unknownKey(m, part, fullPath, curPath)
+	case 1:
+		return m[found[0]], nil
+	}
+	sort.Strings(found)
+	return nil, fetchError(ErrInvalidValue, fullPath, curPath, "config: Ambiguous keys %q at %q", found, curPath)
+}
+
 func fetchValue(cfg interface{}, path string) (interface{}, error) {
-	parts := strings.Split(strings.TrimSpace(path), ".")
+	return fetchParts(cfg, splitPath(strings.TrimSpace(path), defaultSeparator), defaultSeparator)
+}
+
+func fetchParts(cfg interface{}, parts []string, sep string) (interface{}, error) {
+	return fetchWith(cfg, parts, options{sep: sep})
+}
+
+// fetchWith is fetchParts honoring the lookup options: with CaseInsensitive
+// an exact key still wins, otherwise the single key equal under case folding
+// is used and several such keys make the segment ambiguous; with
+// NegativeIndices -1 names the last list element. Lookup failures are
+// reported as *PathError; descending into a null value is ErrNotFound with
+// FailedAt naming the null.
+func fetchWith(cfg interface{}, parts []string, o options) (interface{}, error) {
+	sep := o.separator()
+	fullPath := joinSegments(parts, sep)
 	for pos, part := range parts {
 		if len(strings.TrimSpace(part)) == 0 {
 			continue
 		}
-		curPath := strings.Join(parts[0:pos+1], ".")
+		curPath := joinSegments(parts[0:pos+1], sep)
 		switch c := cfg.(type) {
 		case []interface{}:
 			if ix, error := strconv.ParseInt(part, 10, 0); error == nil {
-				if int(ix) < len(c) {
+				if ix < 0 && o.negativeIndices {
+					ix += int64(len(c))
+				}
+				if ix < 0 && !o.negativeIndices {
+					return nil, fetchError(ErrNotFound, fullPath, curPath, "config: Negative index at %q", curPath)
+				}
+				if ix >= 0 && ix < int64(len(c)) {
 					cfg = c[ix]
 				} else {
-					return nil, fmt.Errorf("config: Index out of bound at %q", curPath)
+					return nil, fetchError(ErrNotFound, fullPath, curPath, "config: Index out of bound at %q", curPath)
 				}
 			} else {
-				return nil, fmt.Errorf("config: Unknown type at %q", curPath)
+				return nil, fetchError(ErrWrongType, fullPath, curPath, "config: expected integer list index at %q, got %q", curPath, part)
 			}
 		case map[string]interface{}:
 			if value, ok := c[part]; ok {
 				cfg = value
+			} else if !o.caseInsensitive {
+				return nil, unknownKey(c, part, fullPath, curPath)
+			} else if value, err := foldKey(c, part, fullPath, curPath); err == nil {
+				cfg = value
 			} else {
-				return nil, fmt.Errorf("config: Unknown path at %q", curPath)
+				return nil, err
 			}
+		case nil:
+			nullPath := joinSegments(parts[0:pos], sep)
+			return nil, fetchError(ErrNotFound, fullPath, nullPath, "config: Null value at %q", nullPath)
 		default:
-			return nil, fmt.Errorf("config: Unknown type at %q", curPath)
+			return nil, fetchError(ErrWrongType, fullPath, curPath, "config: Unknown type at %q", curPath)
+		}
+		var err error
+		if cfg, err = expandDirective(cfg, curPath); err != nil {
+			return nil, err
 		}
 	}
 	return cfg, nil
@@ -247,22 +594,70 @@ func fetchValue(cfg interface{}, path string) (interface{}, error) {
 
 //JSON
 
-func parseJSON(data []byte) (Config, error) {
+func parseJSON(data []byte, o options) (*ConfigImpl, error) {
 	var out map[string]interface{}
-	if err := json.Unmarshal(data, &out); err != nil {
+	data, err := decodeText(data)
+	if err != nil {
 		return nil, err
 	}
-	return &ConfigImpl{root: out}, nil
+	if o.strictKeys {
+		if err := checkDuplicateKeys(data, o.separator()); err != nil {
+			return nil, err
+		}
+	}
+	if o.lazy && o.keyStyle == KeepKeys && !hasDecoders() && !isJSONArray(data) {
+		return parseJSONLazy(data, o)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if o.useNumber {
+		dec.UseNumber()
+	}
+	if isJSONArray(data) {
+		if out, err = decodeArrayRoot(dec); err != nil {
+			return nil, err
+		}
+		return newParsed(out, o)
+	}
+	if err := dec.Decode(&out); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("config: unexpected data after JSON document")
+	}
+	return newParsed(out, o)
 }
 
-func ParseJSON(data string) (Config, error) {
-	return parseJSON([]byte(data))
+func parseJSONFile(path string, o options) (*ConfigImpl, error) {
+	cb, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := parseJSON(cb, o)
+	if err != nil {
+		return nil, err
+	}
+	cfg.origin = fileSource(path)
+	return cfg, nil
 }
 
-func ParseJSONFile(path string) (Config, error) {
-	cb, err := ioutil.ReadFile(path)
+//ParseJSON parses a JSON document. The top level is usually an object; a
+//top-level array is accepted too, with its elements stored under their
+//indices.
+func ParseJSON(data string, opts ...Option) (Config, error) {
+	return parseJSON([]byte(data), newOptions(opts))
+}
+
+func ParseJSONFile(path string, opts ...Option) (Config, error) {
+	return parseJSONFile(path, newOptions(opts))
+}
+
+// ParseJSONReader reads and parses a JSON document from r. Like the other
+// JSON parsers it accepts text with a UTF-8 byte order mark, which is
+// ignored, and UTF-16 text with a byte order mark, which is converted.
+func ParseJSONReader(r io.Reader, opts ...Option) (Config, error) {
+	data, err := ioutil.ReadAll(r)
 	if err != nil {
 		return nil, err
 	}
-	return parseJSON(cb)
+	return parseJSON(data, newOptions(opts))
 }
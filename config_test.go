@@ -1,6 +1,7 @@
 package config_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/mobentum/config"
@@ -64,3 +65,149 @@ func Test_ConfigExtend(t *testing.T) {
 	assert.Equal(t, "production", env)
 	assert.Equal(t, "default", ecfg.MustString("env1", "default"))
 }
+
+func Test_ConfigExtendNotifies(t *testing.T) {
+	cfg, err := config.ParseJSON(`{"env": "dev", "port": 80}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	overlay, err := config.ParseJSON(`{"env": "prod"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var changed []interface{}
+	cfg.OnChange("env", func(old, new interface{}) {
+		changed = append(changed, old, new)
+	})
+	before := cfg.Raw()
+
+	_, err = cfg.Extend(overlay)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"dev", "prod"}, changed)
+	assert.Equal(t, "dev", before["env"])
+	assert.Equal(t, "prod", cfg.MustString("env"))
+	assert.Equal(t, 80, cfg.MustInt("port"))
+}
+
+// stubConfig is a Config implementation other than *config.ConfigImpl.
+type stubConfig struct {
+	config.Config
+	raw map[string]interface{}
+}
+
+func (s stubConfig) Raw() map[string]interface{} {
+	return s.raw
+}
+
+func Test_ConfigExtendForeignConfig(t *testing.T) {
+	cfg, err := config.ParseJSON(`{"env": "default", "debug": true}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out config.Config
+	assert.NotPanics(t, func() {
+		out, err = cfg.Extend(stubConfig{raw: map[string]interface{}{"env": "stub"}})
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "stub", out.MustString("env"))
+	assert.True(t, out.MustBool("debug"))
+
+	merged, err := cfg.Merge(stubConfig{raw: map[string]interface{}{"env": "merged"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "merged", merged.MustString("env"))
+}
+
+func Test_ConfigRaw(t *testing.T) {
+	cfg, err := config.ParseJSON(`{"db": {"host": "a"}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cp := cfg.RawCopy()
+	cp["db"].(map[string]interface{})["host"] = "b"
+	assert.Equal(t, "a", cfg.MustString("db.host"))
+
+	raw := cfg.Raw()
+	assert.Equal(t, map[string]interface{}{"db": map[string]interface{}{"host": "a"}}, raw)
+	raw["db"].(map[string]interface{})["host"] = "c"
+	assert.Equal(t, "c", cfg.MustString("db.host"))
+}
+
+func Test_ConfigMapMerged(t *testing.T) {
+	cfg, err := config.ParseJSONFile("resources/config/default.conf")
+	if err != nil {
+		t.Error(err)
+	}
+
+	defaults := map[string]interface{}{
+		"waist":  30.0,
+		"length": 30.0,
+		"fit":    map[string]interface{}{"style": "slim", "rise": "mid"},
+	}
+	pants, err := cfg.MapMerged("clothes.pants", defaults)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"waist":  32.0,
+		"height": 32.0,
+		"length": 30.0,
+		"fit":    map[string]interface{}{"style": "slim", "rise": "mid"},
+	}, pants)
+	assert.Equal(t, 30.0, defaults["waist"])
+
+	shoes, err := cfg.MapMerged("clothes.shoes", map[string]interface{}{"size": 10.0})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"size": 10.0}, shoes)
+
+	_, err = cfg.MapMerged("name", defaults)
+	assert.True(t, errors.Is(err, config.ErrWrongType))
+}
+
+func Test_ConfigIsNull(t *testing.T) {
+	cfg, err := config.ParseJSON(`{"proxy": null, "port": 0, "db": {"replica": null}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	null, err := cfg.IsNull("proxy")
+	assert.NoError(t, err)
+	assert.True(t, null)
+	null, err = cfg.IsNull("port")
+	assert.NoError(t, err)
+	assert.False(t, null)
+	_, err = cfg.IsNull("missing")
+	assert.True(t, errors.Is(err, config.ErrNotFound))
+	assert.True(t, cfg.Has("db.replica"))
+
+	m, err := cfg.Map("db.replica")
+	assert.Nil(t, m)
+	assert.True(t, errors.Is(err, config.ErrWrongType))
+	assert.EqualError(t, err, `config: Null value at "db.replica"`)
+	_, err = cfg.List("proxy")
+	assert.True(t, errors.Is(err, config.ErrWrongType))
+	_, err = cfg.Int("proxy")
+	assert.EqualError(t, err, `config: Null value at "proxy"`)
+}
+
+func Test_ConfigArrayRoot(t *testing.T) {
+	cfg, err := config.ParseJSONFile("resources/config/rules.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "allow-internal", cfg.MustString("1.name"))
+	assert.Equal(t, "deny", cfg.MustString("0.action"))
+	keys, err := cfg.Keys("")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0", "1", "2"}, keys)
+	_, err = cfg.List("")
+	assert.ErrorIs(t, err, config.ErrWrongType)
+	_, err = cfg.String("3.name")
+	assert.ErrorIs(t, err, config.ErrNotFound)
+
+	lazy, err := config.ParseJSON(`[{"name": "a"}, {"name": "b"}]`, config.Lazy())
+	assert.NoError(t, err)
+	assert.Equal(t, "b", lazy.MustString("1.name"))
+
+	_, err = config.ParseJSON(`[1, 2] 3`)
+	assert.Error(t, err)
+}
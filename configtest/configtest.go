@@ -0,0 +1,131 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package configtest provides helpers for testing code built on the config
+// package, including a conformance harness for format loaders.
+package configtest
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/mobentum/config"
+)
+
+// FromJSON parses s with config.ParseJSON and fails the test if it does not
+// parse.
+func FromJSON(t testing.TB, s string, opts ...config.Option) config.Config {
+	t.Helper()
+	cfg, err := config.ParseJSON(s, opts...)
+	if err != nil {
+		t.Fatalf("configtest: parsing config: %v", err)
+	}
+	return cfg
+}
+
+// FromMap builds a config from m. The map is round-tripped through JSON so
+// numbers become float64 and nested values take the same shapes as a parsed
+// file; it panics if m cannot be encoded as JSON.
+func FromMap(m map[string]interface{}, opts ...config.Option) config.Config {
+	data, err := json.Marshal(m)
+	if err != nil {
+		panic("configtest: encoding map: " + err.Error())
+	}
+	cfg, err := config.ParseJSON(string(data), opts...)
+	if err != nil {
+		panic("configtest: parsing map: " + err.Error())
+	}
+	return cfg
+}
+
+// ConformanceJSON is the logical document every loader under test must
+// reproduce in its own format.
+const ConformanceJSON = `{
+    "name": "conformance",
+    "enabled": true,
+    "port": 8080,
+    "ratio": 0.25,
+    "tags": ["a", "b"],
+    "db": {
+        "host": "db.internal",
+        "port": 5432
+    }
+}`
+
+// RunConformance loads a config with load and checks that every typed
+// accessor returns the same result as for ConformanceJSON parsed by the JSON
+// loader, so a new format behaves exactly like JSON.
+func RunConformance(t *testing.T, load func() (config.Config, error)) {
+	t.Helper()
+	want, err := config.ParseJSON(ConformanceJSON)
+	if err != nil {
+		t.Fatalf("configtest: parsing reference document: %v", err)
+	}
+	got, err := load()
+	if err != nil {
+		t.Fatalf("configtest: loading config: %v", err)
+	}
+
+	check := func(accessor, path string, w, g interface{}) {
+		t.Helper()
+		if !reflect.DeepEqual(w, g) {
+			t.Errorf("configtest: %s(%q) = %#v, want %#v", accessor, path, g, w)
+		}
+	}
+	for _, path := range []string{"name", "db.host", "port", "missing"} {
+		w, werr := want.String(path)
+		g, gerr := got.String(path)
+		check("String", path, w, g)
+		check("String error", path, werr == nil, gerr == nil)
+	}
+	for _, path := range []string{"enabled", "name", "missing"} {
+		w, werr := want.Bool(path)
+		g, gerr := got.Bool(path)
+		check("Bool", path, w, g)
+		check("Bool error", path, werr == nil, gerr == nil)
+	}
+	for _, path := range []string{"port", "db.port", "name", "missing"} {
+		w, werr := want.Int(path)
+		g, gerr := got.Int(path)
+		check("Int", path, w, g)
+		check("Int error", path, werr == nil, gerr == nil)
+	}
+	for _, path := range []string{"ratio", "port", "enabled", "missing"} {
+		w, werr := want.Float(path)
+		g, gerr := got.Float(path)
+		check("Float", path, w, g)
+		check("Float error", path, werr == nil, gerr == nil)
+	}
+	for _, path := range []string{"tags", "db", "missing"} {
+		w, werr := want.List(path)
+		g, gerr := got.List(path)
+		check("List", path, w, g)
+		check("List error", path, werr == nil, gerr == nil)
+	}
+	for _, path := range []string{"db", "tags", "missing"} {
+		w, werr := want.Map(path)
+		g, gerr := got.Map(path)
+		check("Map", path, w, g)
+		check("Map error", path, werr == nil, gerr == nil)
+	}
+	check("String", "tags.1", want.MustString("tags.1"), got.MustString("tags.1"))
+	check("Flatten", "", want.Flatten(), got.Flatten())
+}
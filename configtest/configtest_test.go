@@ -0,0 +1,51 @@
+package configtest_test
+
+import (
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/mobentum/config/configtest"
+)
+
+func Test_ConformanceJSON(t *testing.T) {
+	configtest.RunConformance(t, func() (config.Config, error) {
+		return config.ParseJSONFile("../resources/config/conformance.json")
+	})
+}
+
+func Test_ConformanceProperties(t *testing.T) {
+	configtest.RunConformance(t, func() (config.Config, error) {
+		return config.ParsePropertiesFile("../resources/config/conformance.properties")
+	})
+}
+
+func Test_ConformanceINI(t *testing.T) {
+	configtest.RunConformance(t, func() (config.Config, error) {
+		return config.ParseINIFile("../resources/config/conformance.ini")
+	})
+}
+
+func Test_FromJSON(t *testing.T) {
+	cfg := configtest.FromJSON(t, `{"db": {"port": 5432}}`)
+	if got := cfg.MustInt("db.port"); got != 5432 {
+		t.Errorf("db.port = %d, want 5432", got)
+	}
+}
+
+func Test_FromMap(t *testing.T) {
+	cfg := configtest.FromMap(map[string]interface{}{
+		"replicas": 3,
+		"tags":     []string{"a", "b"},
+		"db":       map[string]interface{}{"port": int64(5432)},
+	})
+	raw := cfg.Raw()
+	if x := raw["replicas"]; x != 3.0 {
+		t.Errorf("replicas = %#v, want float64 3", x)
+	}
+	if x := raw["db"].(map[string]interface{})["port"]; x != 5432.0 {
+		t.Errorf("db.port = %#v, want float64 5432", x)
+	}
+	if got := cfg.MustString("tags.1"); got != "b" {
+		t.Errorf("tags.1 = %q, want %q", got, "b")
+	}
+}
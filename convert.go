@@ -0,0 +1,195 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Numbers decoded from JSON are float64, but values built in Go may hold any
+// numeric type. The numeric getters widen uniformly:
+//
+//	getter      accepts
+//	Int         float64/float32 with no fractional part, any int or uint type
+//	Float       float64, float32, any int or uint type
+//	String      string; numbers and bools too with WithStringCoercion
+//	BoolCoerce  bool, the numbers 1 and 0, and the strings listed at BoolCoerce
+//
+// A conversion that would lose information (a fractional part, or a value
+// out of range for the target type) is reported as ErrWrongType.
+//
+// With the UseNumber option numbers are decoded as json.Number instead. Int,
+// Int64 and Uint64 then read integers exactly from their text, even beyond
+// the 2^53 that float64 represents exactly, while Float parses the text as
+// before.
+
+// toFloat64 widens any numeric value to float64.
+func toFloat64(x interface{}) (float64, bool) {
+	switch v := x.(type) {
+	case float64:
+		return v, true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// toInt64 converts integral numeric values to int64.
+func toInt64(x interface{}) (int64, bool) {
+	switch v := x.(type) {
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint:
+		return int64(v), uint64(v) <= math.MaxInt64
+	case uint8:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case uint64:
+		return int64(v), v <= math.MaxInt64
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, true
+		}
+	}
+	f, ok := toFloat64(x)
+	if !ok || f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, false
+	}
+	return int64(f), true
+}
+
+// toUint64 converts non-negative integral numeric values to uint64.
+func toUint64(x interface{}) (uint64, bool) {
+	switch v := x.(type) {
+	case uint:
+		return uint64(v), true
+	case uint64:
+		return v, true
+	case json.Number:
+		if u, err := strconv.ParseUint(string(v), 10, 64); err == nil {
+			return u, true
+		}
+	}
+	if i, ok := toInt64(x); ok {
+		return uint64(i), i >= 0
+	}
+	f, ok := toFloat64(x)
+	if !ok || f != math.Trunc(f) || f < 0 || f >= math.MaxUint64 {
+		return 0, false
+	}
+	return uint64(f), true
+}
+
+// coerceString renders numbers and bools as strings. Integers are formatted
+// exactly, so values beyond 2^53 such as IDs keep every digit; only real
+// floats go through float formatting.
+func coerceString(x interface{}) (string, bool) {
+	switch v := x.(type) {
+	case bool:
+		return strconv.FormatBool(v), true
+	case json.Number:
+		return v.String(), true
+	case int:
+		return strconv.FormatInt(int64(v), 10), true
+	case int8:
+		return strconv.FormatInt(int64(v), 10), true
+	case int16:
+		return strconv.FormatInt(int64(v), 10), true
+	case int32:
+		return strconv.FormatInt(int64(v), 10), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case uint:
+		return strconv.FormatUint(uint64(v), 10), true
+	case uint8:
+		return strconv.FormatUint(uint64(v), 10), true
+	case uint16:
+		return strconv.FormatUint(uint64(v), 10), true
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10), true
+	case uint64:
+		return strconv.FormatUint(v, 10), true
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	}
+	return "", false
+}
+
+// coerceBool reads the numbers 1 and 0 and the strings accepted by
+// strconv.ParseBool, plus yes/no and on/off in any case, as bools.
+func coerceBool(x interface{}) (bool, bool) {
+	if b, ok := x.(bool); ok {
+		return b, true
+	}
+	if s, ok := x.(string); ok {
+		switch strings.ToLower(strings.TrimSpace(s)) {
+		case "yes", "on":
+			return true, true
+		case "no", "off":
+			return false, true
+		}
+		b, err := strconv.ParseBool(strings.TrimSpace(s))
+		return b, err == nil
+	}
+	if i, ok := toInt64(x); ok && (i == 0 || i == 1) {
+		return i == 1, true
+	}
+	return false, false
+}
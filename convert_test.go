@@ -0,0 +1,136 @@
+package config_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigWidening(t *testing.T) {
+	cfg, err := config.Unflatten(map[string]interface{}{
+		"count":    26.0,
+		"height":   5.10,
+		"int":      7,
+		"int64":    int64(1) << 40,
+		"uint8":    uint8(200),
+		"float32":  float32(2.5),
+		"debug":    true,
+		"name":     "John",
+		"overflow": 1e300,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 26, cfg.MustInt("count"))
+	assert.Equal(t, 7, cfg.MustInt("int"))
+	assert.Equal(t, 1<<40, cfg.MustInt("int64"))
+	assert.Equal(t, 200, cfg.MustInt("uint8"))
+	_, err = cfg.Int("height")
+	assert.Error(t, err)
+	_, err = cfg.Int("float32")
+	assert.Error(t, err)
+	_, err = cfg.Int("overflow")
+	assert.Error(t, err)
+
+	assert.Equal(t, 26.0, cfg.MustFloat("count"))
+	assert.Equal(t, 7.0, cfg.MustFloat("int"))
+	assert.Equal(t, 200.0, cfg.MustFloat("uint8"))
+	assert.Equal(t, 2.5, cfg.MustFloat("float32"))
+
+	_, err = cfg.String("count")
+	assert.Error(t, err)
+}
+
+func Test_ConfigStringCoercion(t *testing.T) {
+	cfg, err := config.ParseJSON(`{"port": 8080, "height": 5.10, "debug": true, "name": "John", "tags": []}`, config.WithStringCoercion())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "8080", cfg.MustString("port"))
+	assert.Equal(t, "5.1", cfg.MustString("height"))
+	assert.Equal(t, "true", cfg.MustString("debug"))
+	assert.Equal(t, "John", cfg.MustString("name"))
+	_, err = cfg.String("tags")
+	assert.Error(t, err)
+
+	assert.NoError(t, cfg.Set("id", 9007199254740993))
+	assert.Equal(t, "9007199254740993", cfg.MustString("id"))
+	assert.NoError(t, cfg.Set("max", uint(18446744073709551615)))
+	assert.Equal(t, "18446744073709551615", cfg.MustString("max"))
+	assert.NoError(t, cfg.Set("ratio", float32(0.1)))
+	assert.Equal(t, "0.1", cfg.MustString("ratio"))
+}
+
+func Test_ConfigInt64(t *testing.T) {
+	cfg, err := config.ParseJSON(`{
+		"id": 9007199254740992,
+		"big": 1e19,
+		"huge": 1e20,
+		"negative": -42,
+		"fraction": 1.5
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := cfg.Int64("id")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9007199254740992), id)
+	assert.Equal(t, int64(-42), cfg.MustInt64("negative"))
+	_, err = cfg.Int64("big")
+	assert.True(t, errors.Is(err, config.ErrWrongType))
+	assert.Equal(t, int64(7), cfg.MustInt64("fraction", 7))
+
+	big, err := cfg.Uint64("big")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10000000000000000000), big)
+	assert.Equal(t, uint64(9007199254740992), cfg.MustUint64("id"))
+	_, err = cfg.Uint64("huge")
+	assert.True(t, errors.Is(err, config.ErrWrongType))
+	_, err = cfg.Uint64("negative")
+	assert.True(t, errors.Is(err, config.ErrWrongType))
+	assert.Equal(t, uint64(0), cfg.MustUint64("fraction"))
+}
+
+func Test_ConfigUseNumber(t *testing.T) {
+	doc := `{"id": 18446744073709551615, "big": 1234567890123456789, "neg": -1234567890123456789, "ratio": 0.25, "limit": "5/s", "r": {"$range": {"start": 1, "end": 3}}}`
+
+	cfg, err := config.ParseJSON(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	i, err := cfg.Int64("big")
+	assert.NoError(t, err)
+	assert.NotEqual(t, int64(1234567890123456789), i)
+
+	cfg, err = config.ParseJSON(doc, config.UseNumber())
+	if err != nil {
+		t.Fatal(err)
+	}
+	i, err = cfg.Int64("big")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1234567890123456789), i)
+	assert.Equal(t, int64(-1234567890123456789), cfg.MustInt64("neg"))
+	assert.Equal(t, 1234567890123456789, cfg.MustInt("big"))
+	assert.Equal(t, uint64(18446744073709551615), cfg.MustUint64("id"))
+	_, err = cfg.Int64("id")
+	assert.Error(t, err)
+	assert.Equal(t, 0.25, cfg.MustFloat("ratio"))
+	_, err = cfg.Int("ratio")
+	assert.Error(t, err)
+	assert.Equal(t, 5.0, cfg.MustRate("limit"))
+	assert.Equal(t, []interface{}{1.0, 2.0, 3.0}, cfg.MustList("r"))
+
+	cfg, err = config.ParseJSON(`{"s": 1234567890123456789}`, config.UseNumber(), config.WithStringCoercion())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "1234567890123456789", cfg.MustString("s"))
+
+	_, err = config.ParseJSON(`{"a": 1} {"b": 2}`)
+	assert.Error(t, err)
+}
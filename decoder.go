@@ -0,0 +1,132 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	decodersMu sync.RWMutex
+	decoders   = map[string]func(string) (string, error){}
+)
+
+// RegisterDecoder transforms string values that start with prefix when a
+// config is parsed: fn receives the rest of the value and returns the value
+// to store. It lets secrets be kept encrypted in files, e.g. as
+// "enc:BASE64...", and decrypted by an application-supplied KMS client
+// without this package depending on one. When prefixes overlap the longest
+// matching one is used, and a decoder's output is not decoded again. An
+// error from fn fails the parse with ErrInvalidValue, naming the path but not
+// the value. Registering a prefix again replaces its decoder and a nil fn
+// removes it. Values set after parsing are stored as given.
+func RegisterDecoder(prefix string, fn func(string) (string, error)) {
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+	if fn == nil {
+		delete(decoders, prefix)
+		return
+	}
+	decoders[prefix] = fn
+}
+
+// ResetDecoders removes every decoder registered with RegisterDecoder.
+func ResetDecoders() {
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+	decoders = map[string]func(string) (string, error){}
+}
+
+// hasDecoders reports whether any decoder is registered.
+func hasDecoders() bool {
+	decodersMu.RLock()
+	defer decodersMu.RUnlock()
+	return len(decoders) > 0
+}
+
+// applyDecoders runs the registered decoders over the string values of node
+// in place.
+func applyDecoders(node interface{}, path, sep string) error {
+	decodersMu.RLock()
+	prefixes := make([]string, 0, len(decoders))
+	fns := make(map[string]func(string) (string, error), len(decoders))
+	for p, fn := range decoders {
+		prefixes = append(prefixes, p)
+		fns[p] = fn
+	}
+	decodersMu.RUnlock()
+	if len(prefixes) == 0 {
+		return nil
+	}
+	sort.Slice(prefixes, func(i, j int) bool { return len(prefixes[i]) > len(prefixes[j]) })
+
+	decode := func(v interface{}, path string) (interface{}, error) {
+		s, ok := v.(string)
+		if !ok {
+			return v, nil
+		}
+		for _, p := range prefixes {
+			if !strings.HasPrefix(s, p) {
+				continue
+			}
+			out, err := fns[p](s[len(p):])
+			if err != nil {
+				return nil, fetchError(ErrInvalidValue, path, path, "config: Cannot decode %q value at %q: %v", p, path, err)
+			}
+			return out, nil
+		}
+		return s, nil
+	}
+	var visit func(node interface{}, path string) error
+	visit = func(node interface{}, path string) error {
+		switch x := node.(type) {
+		case map[string]interface{}:
+			for k, v := range x {
+				p := joinPath(path, k, sep)
+				out, err := decode(v, p)
+				if err != nil {
+					return err
+				}
+				x[k] = out
+				if err := visit(out, p); err != nil {
+					return err
+				}
+			}
+		case []interface{}:
+			for i, v := range x {
+				p := joinPath(path, strconv.Itoa(i), sep)
+				out, err := decode(v, p)
+				if err != nil {
+					return err
+				}
+				x[i] = out
+				if err := visit(out, p); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	return visit(node, path)
+}
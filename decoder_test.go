@@ -0,0 +1,47 @@
+package config_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func rot13(s string) (string, error) {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return 'a' + (r-'a'+13)%26
+		case r >= 'A' && r <= 'Z':
+			return 'A' + (r-'A'+13)%26
+		}
+		return r
+	}, s), nil
+}
+
+func Test_ConfigRegisterDecoder(t *testing.T) {
+	defer config.ResetDecoders()
+	config.RegisterDecoder("rot13:", rot13)
+	config.RegisterDecoder("fail:", func(string) (string, error) {
+		return "", errors.New("kms unavailable")
+	})
+
+	cfg, err := config.ParseJSON(`{"db": {"password": "rot13:frperg", "user": "app"}, "keys": ["rot13:nop"]}`, config.Lazy())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "secret", cfg.MustString("db.password"))
+	assert.Equal(t, "app", cfg.MustString("db.user"))
+	assert.Equal(t, "abc", cfg.MustString("keys.0"))
+
+	_, err = config.ParseJSON(`{"token": "fail:xyz"}`)
+	assert.True(t, errors.Is(err, config.ErrInvalidValue))
+	assert.EqualError(t, err, `config: Cannot decode "fail:" value at "token": kms unavailable`)
+
+	config.RegisterDecoder("fail:", nil)
+	cfg, err = config.ParseJSON(`{"token": "fail:xyz"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "fail:xyz", cfg.MustString("token"))
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Lookups resolve with this precedence: a value present in the config, then
+// the default passed to a Must getter, then a global default registered with
+// SetGlobalDefault. Defaults written in the config file itself are folded
+// into the config by ApplyDefaults and from then on count as present.
+
+// legacySentinels is set by UseLegacyMustSentinels.
+var legacySentinels int32
+
+// UseLegacyMustSentinels restores the -1 that MustInt, MustInt64, MustFloat,
+// MustRate and MustBytes used to return when a path is missing or mistyped
+// and no default is given. They now return 0, like the other Must getters
+// return their zero value; call UseLegacyMustSentinels(true) once at startup
+// in programs that test for -1. Getters added since, such as MustDuration
+// and MustPercent, never returned -1 and always return 0.
+func UseLegacyMustSentinels(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&legacySentinels, v)
+}
+
+// missingNumber is what numeric Must getters return without a value or a
+// default.
+func missingNumber() int64 {
+	if atomic.LoadInt32(&legacySentinels) == 1 {
+		return -1
+	}
+	return 0
+}
+
+// defaultDefaultsKey is the top-level key read by ApplyDefaults.
+const defaultDefaultsKey = "$defaults"
+
+var (
+	globalMu       sync.RWMutex
+	globalDefaults = map[string]interface{}{}
+)
+
+// SetGlobalDefault registers a package-wide default for the dotted path,
+// consulted by every config's accessors when the path is absent from it.
+// Libraries can use it to ship sane defaults that applications override in
+// their own config files. A map value provides defaults for the paths below
+// it as well.
+func SetGlobalDefault(path string, value interface{}) {
+	parts := splitPath(path, defaultSeparator)
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	node := globalDefaults
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := node[part].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			node[part] = next
+		}
+		node = next
+	}
+	node[parts[len(parts)-1]] = copyValue(value)
+}
+
+// ResetGlobalDefaults removes every global default.
+func ResetGlobalDefaults() {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	globalDefaults = map[string]interface{}{}
+}
+
+func globalDefault(parts []string) (interface{}, bool) {
+	globalMu.RLock()
+	defer globalMu.RUnlock()
+	v, err := fetchParts(globalDefaults, parts, defaultSeparator)
+	if err != nil {
+		return nil, false
+	}
+	return copyValue(v), true
+}
+
+// WithDefaultsKey sets the top-level key ApplyDefaults reads, "$defaults" by
+// default.
+func WithDefaultsKey(key string) Option {
+	return func(o *options) {
+		o.defaultsKey = key
+	}
+}
+
+// ApplyDefaults folds the defaults declared in the config itself into it. The
+// top-level "$defaults" object (see WithDefaultsKey) mirrors the layout of
+// the config, and each of its values fills the same path where the config
+// has none:
+//
+//	{"$defaults": {"db": {"port": 5432, "pool": 10}}, "db": {"pool": 50}}
+//
+// becomes {"db": {"port": 5432, "pool": 50}}. Values present in the config,
+// including null, always win over $defaults; maps are merged key by key and
+// a present list replaces a default list whole. The $defaults key is then
+// removed. Without it ApplyDefaults does nothing; a $defaults that is not an
+// object is ErrWrongType.
+func (c *ConfigImpl) ApplyDefaults() error {
+	defer c.beginWrite()()
+	key := c.opts.defaultsKey
+	if key == "" {
+		key = defaultDefaultsKey
+	}
+	root := c.tree()
+	x, ok := root[key]
+	if !ok {
+		return nil
+	}
+	defaults, ok := x.(map[string]interface{})
+	if !ok {
+		return newPathError(ErrWrongType, "config: %s must be an object, got %s", key, jsonTypeName(x))
+	}
+	next := copyValue(defaults).(map[string]interface{})
+	rest := make(map[string]interface{}, len(root))
+	for k, v := range root {
+		if k != key {
+			rest[k] = v
+		}
+	}
+	mergeDeep(next, rest)
+	return c.commit(next)
+}
@@ -0,0 +1,97 @@
+package config_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigGlobalDefaults(t *testing.T) {
+	defer config.ResetGlobalDefaults()
+	config.SetGlobalDefault("server.port", 8080)
+	config.SetGlobalDefault("server.timeouts", map[string]interface{}{"read": 5.0})
+	config.SetGlobalDefault("name", "fallback")
+
+	cfg, err := config.ParseJSONFile("resources/config/default.conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	port, err := cfg.Int("server.port")
+	assert.NoError(t, err)
+	assert.Equal(t, 8080, port)
+	assert.Equal(t, 8080, cfg.MustInt("server.port"))
+	assert.Equal(t, 5, cfg.MustInt("server.timeouts.read"))
+	assert.False(t, cfg.Has("server.port"))
+
+	assert.Equal(t, "John", cfg.MustString("name"))
+	assert.Equal(t, "John", cfg.MustString("name", "instance"))
+	assert.Equal(t, 9090, cfg.MustInt("server.port", 9090))
+
+	config.ResetGlobalDefaults()
+	_, err = cfg.Int("server.port")
+	assert.Error(t, err)
+}
+
+func Test_ConfigApplyDefaults(t *testing.T) {
+	cfg, err := config.ParseJSON(`{
+		"$defaults": {"db": {"host": "localhost", "port": 5432, "pool": 10}, "debug": false, "proxy": "none"},
+		"db": {"host": "db.internal", "pool": 50},
+		"proxy": null
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NoError(t, cfg.ApplyDefaults())
+	assert.Equal(t, "db.internal", cfg.MustString("db.host"))
+	assert.Equal(t, 50, cfg.MustInt("db.pool"))
+	assert.Equal(t, 5432, cfg.MustInt("db.port"))
+	assert.False(t, cfg.MustBool("debug", true))
+	null, err := cfg.IsNull("proxy")
+	assert.NoError(t, err)
+	assert.True(t, null)
+	assert.False(t, cfg.Has("$defaults"))
+	assert.NoError(t, cfg.ApplyDefaults())
+
+	cfg, err = config.ParseJSON(`{"_defaults": {"port": 80}, "$defaults": {"port": 1}}`, config.WithDefaultsKey("_defaults"))
+	assert.NoError(t, err)
+	assert.NoError(t, cfg.ApplyDefaults())
+	assert.Equal(t, 80, cfg.MustInt("port"))
+	assert.True(t, cfg.Has("$defaults"))
+
+	cfg, err = config.ParseJSON(`{"$defaults": [1]}`)
+	assert.NoError(t, err)
+	assert.True(t, errors.Is(cfg.ApplyDefaults(), config.ErrWrongType))
+}
+
+func Test_ConfigMustZeroValues(t *testing.T) {
+	cfg, err := config.ParseJSON(`{"name": "api"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 0, cfg.MustInt("missing"))
+	assert.Equal(t, int64(0), cfg.MustInt64("name"))
+	assert.Equal(t, 0.0, cfg.MustFloat("missing"))
+	assert.Equal(t, 0.0, cfg.MustRate("missing"))
+	assert.Equal(t, int64(0), cfg.MustBytes("missing"))
+	assert.Equal(t, "", cfg.MustString("missing"))
+	assert.False(t, cfg.MustBool("missing"))
+	assert.Empty(t, cfg.MustList("missing"))
+	assert.Empty(t, cfg.MustMap("missing"))
+
+	config.UseLegacyMustSentinels(true)
+	defer config.UseLegacyMustSentinels(false)
+	assert.Equal(t, -1, cfg.MustInt("missing"))
+	assert.Equal(t, int64(-1), cfg.MustInt64("name"))
+	assert.Equal(t, -1.0, cfg.MustFloat("missing"))
+	assert.Equal(t, -1.0, cfg.MustRate("missing"))
+	assert.Equal(t, int64(-1), cfg.MustBytes("missing"))
+	assert.Equal(t, 8080, cfg.MustInt("missing", 8080))
+	assert.Equal(t, time.Duration(0), cfg.MustDuration("missing"))
+	assert.Equal(t, 0.0, cfg.MustPercent("missing"))
+}
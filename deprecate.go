@@ -0,0 +1,69 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"fmt"
+)
+
+// deprecation records a path renamed from old to new.
+type deprecation struct {
+	old, new string
+}
+
+// RegisterDeprecated records that oldPath has been renamed to newPath, for
+// CheckDeprecated to report. Registering oldPath again replaces its
+// replacement. Pair it with FirstOf to keep reading the old name:
+//
+//	cfg.RegisterDeprecated("server.address", "server.addr")
+//	addr, err := cfg.StringFirstOf("server.addr", "server.address")
+func (c *ConfigImpl) RegisterDeprecated(oldPath, newPath string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, d := range c.deprecated {
+		if d.old == oldPath {
+			c.deprecated[i].new = newPath
+			return
+		}
+	}
+	c.deprecated = append(c.deprecated, deprecation{oldPath, newPath})
+}
+
+// CheckDeprecated returns a warning for each registered deprecated path that
+// is present in the config, in registration order, naming its replacement.
+// The package never logs them itself, so callers decide where they go.
+func (c *ConfigImpl) CheckDeprecated() []string {
+	c.mu.RLock()
+	deprecated := append([]deprecation(nil), c.deprecated...)
+	c.mu.RUnlock()
+	var warnings []string
+	for _, d := range deprecated {
+		if !c.Has(d.old) {
+			continue
+		}
+		w := fmt.Sprintf("config: %q is deprecated, use %q instead", d.old, d.new)
+		if c.Has(d.new) {
+			w += fmt.Sprintf("; %q is also set", d.new)
+		}
+		warnings = append(warnings, w)
+	}
+	return warnings
+}
@@ -0,0 +1,30 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigCheckDeprecated(t *testing.T) {
+	cfg, err := config.ParseJSON(`{"server": {"address": ":8080"}, "workers": 4, "threads": 2}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, cfg.CheckDeprecated())
+
+	cfg.RegisterDeprecated("server.address", "server.addr")
+	cfg.RegisterDeprecated("timeout", "server.timeout")
+	cfg.RegisterDeprecated("threads", "pool")
+	cfg.RegisterDeprecated("threads", "workers")
+	assert.Equal(t, []string{
+		`config: "server.address" is deprecated, use "server.addr" instead`,
+		`config: "threads" is deprecated, use "workers" instead; "workers" is also set`,
+	}, cfg.CheckDeprecated())
+
+	assert.NoError(t, cfg.Set("server.addr", ":9090"))
+	assert.NoError(t, cfg.Delete("server.address"))
+	assert.NoError(t, cfg.Delete("threads"))
+	assert.Empty(t, cfg.CheckDeprecated())
+}
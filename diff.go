@@ -0,0 +1,90 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"reflect"
+	"sort"
+)
+
+// ChangeKind classifies a Change.
+type ChangeKind int
+
+// Kinds of Change.
+const (
+	Added ChangeKind = iota + 1
+	Removed
+	Modified
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Modified:
+		return "modified"
+	}
+	return "unknown"
+}
+
+// Change is one differing leaf reported by Diff. Old is nil for Added and
+// New is nil for Removed.
+type Change struct {
+	Path     string
+	Old, New interface{}
+	Kind     ChangeKind
+}
+
+// Diff compares the leaves of a and b, as returned by Flatten, and reports
+// every leaf that was added, removed or modified going from a to b, sorted by
+// path. A leaf replaced by a subtree shows up as the leaf being removed and
+// the leaves below it being added. A nil config has no leaves.
+func Diff(a, b Config) []Change {
+	before, after := flattenOrEmpty(a), flattenOrEmpty(b)
+	var changes []Change
+	for path, old := range before {
+		nv, ok := after[path]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Path: path, Old: old, Kind: Removed})
+		case !reflect.DeepEqual(old, nv):
+			changes = append(changes, Change{Path: path, Old: old, New: nv, Kind: Modified})
+		}
+	}
+	for path, nv := range after {
+		if _, ok := before[path]; !ok {
+			changes = append(changes, Change{Path: path, New: nv, Kind: Added})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+func flattenOrEmpty(c Config) map[string]interface{} {
+	if c == nil {
+		return map[string]interface{}{}
+	}
+	return c.Flatten()
+}
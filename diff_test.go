@@ -0,0 +1,41 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigDiff(t *testing.T) {
+	staging, err := config.ParseJSON(`{
+		"replicas": 2,
+		"db": {"host": "staging-db", "pool": 5},
+		"debug": true,
+		"limit": 10
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prod, err := config.ParseJSON(`{
+		"replicas": 2,
+		"db": {"host": "prod-db", "pool": 5, "ssl": true},
+		"limit": "10",
+		"cache": {"ttl": 60}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []config.Change{
+		{Path: "cache.ttl", New: 60.0, Kind: config.Added},
+		{Path: "db.host", Old: "staging-db", New: "prod-db", Kind: config.Modified},
+		{Path: "db.ssl", New: true, Kind: config.Added},
+		{Path: "debug", Old: true, Kind: config.Removed},
+		{Path: "limit", Old: 10.0, New: "10", Kind: config.Modified},
+	}, config.Diff(staging, prod))
+
+	assert.Empty(t, config.Diff(prod, prod))
+	assert.Len(t, config.Diff(nil, prod), 6)
+	assert.Equal(t, "removed", config.Removed.String())
+}
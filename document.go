@@ -0,0 +1,386 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Document is a config parsed from JSON or JSONC that keeps its source text,
+// so that it can be edited with Set and Delete and written back with the
+// author's layout intact. It offers every Config getter and mutation.
+//
+// Bytes and Save reproduce the source byte for byte except where values
+// changed. Comments, whitespace, key order, number spellings and string
+// escapes are preserved outside the changed values:
+//   - a changed value is rewritten in place as JSON;
+//   - keys added to an existing object are appended after its last member,
+//     in sorted order, on lines of their own when the object spans lines;
+//   - an object that lost keys, or a list whose length changed, is rewritten
+//     as a whole, indented to match, which drops comments inside it.
+//
+// Text read with a byte order mark is written back as UTF-8 without one.
+//
+// A Document is safe for concurrent reads and changes, but Save should not be
+// called concurrently with itself.
+type Document struct {
+	*ConfigImpl
+	saveMu sync.Mutex
+	path   string
+	src    []byte
+	orig   interface{}
+	spans  map[string]docSpan
+	indent string
+}
+
+// docSpan locates a value in the source. For objects, last is the end of
+// the value of the last member (-1 when empty), lastKey the start of its key.
+type docSpan struct {
+	start, end    int
+	last, lastKey int
+}
+
+// ParseJSONDocument parses JSON or JSONC into a Document.
+func ParseJSONDocument(data string, opts ...Option) (*Document, error) {
+	return parseDocument([]byte(data), newOptions(opts))
+}
+
+// ParseJSONDocumentFile reads and parses a JSON or JSONC file into a Document
+// that Save writes back to.
+func ParseJSONDocumentFile(path string, opts ...Option) (*Document, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	d, err := parseDocument(data, newOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	d.path = path
+	d.origin = fileSource(path)
+	return d, nil
+}
+
+func parseDocument(data []byte, o options) (*Document, error) {
+	if o.keyStyle != KeepKeys {
+		return nil, errors.New("config: a Document cannot be parsed with NormalizeKeys")
+	}
+	data, err := decodeText(data)
+	if err != nil {
+		return nil, err
+	}
+	stripped, err := stripJSONC(data)
+	if err != nil {
+		return nil, err
+	}
+	if isJSONArray(stripped) {
+		return nil, errors.New("config: a Document must have an object at the top level")
+	}
+	cfg, err := parseJSON(stripped, o)
+	if err != nil {
+		return nil, err
+	}
+	d := &Document{ConfigImpl: cfg}
+	d.load(data, stripped)
+	return d, nil
+}
+
+// load records data as the saved state of the document.
+func (d *Document) load(data, stripped []byte) {
+	d.src = data
+	d.orig = copyValue(d.tree())
+	s := &docScanner{b: stripped, spans: map[string]docSpan{}}
+	s.value("")
+	d.spans = s.spans
+	d.indent = "  "
+	if root, ok := d.spans[""]; ok && root.lastKey >= 0 {
+		first := bytes.IndexByte(stripped[root.start:], '"') + root.start
+		if bytes.IndexByte(stripped[root.start:first], '\n') >= 0 {
+			if ind := lineIndent(data, first); ind != "" {
+				d.indent = ind
+			}
+		}
+	}
+}
+
+// Bytes renders the document with its changes applied to the source text.
+func (d *Document) Bytes() ([]byte, error) {
+	d.saveMu.Lock()
+	defer d.saveMu.Unlock()
+	return d.render()
+}
+
+// Save writes the document back to the file it was read from, after which
+// the written text is the baseline for further changes.
+func (d *Document) Save() error {
+	d.saveMu.Lock()
+	defer d.saveMu.Unlock()
+	if d.path == "" {
+		return errors.New("config: Save requires a document read with ParseJSONDocumentFile")
+	}
+	out, err := d.render()
+	if err != nil {
+		return err
+	}
+	mode := os.FileMode(0644)
+	if fi, err := os.Stat(d.path); err == nil {
+		mode = fi.Mode().Perm()
+	}
+	if err := ioutil.WriteFile(d.path, out, mode); err != nil {
+		return err
+	}
+	stripped, err := stripJSONC(out)
+	if err != nil {
+		return err
+	}
+	d.load(out, stripped)
+	return nil
+}
+
+// docEdit replaces src[start:end] with text.
+type docEdit struct {
+	start, end int
+	text       string
+}
+
+func (d *Document) render() ([]byte, error) {
+	var edits []docEdit
+	if err := d.diff(d.orig, d.tree(), "", &edits); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+	var out bytes.Buffer
+	pos := 0
+	for _, e := range edits {
+		out.Write(d.src[pos:e.start])
+		out.WriteString(e.text)
+		pos = e.end
+	}
+	out.Write(d.src[pos:])
+	return out.Bytes(), nil
+}
+
+// diff appends the edits turning the value orig, found in the source at key,
+// into cur.
+func (d *Document) diff(orig, cur interface{}, key string, edits *[]docEdit) error {
+	span := d.spans[key]
+	switch o := orig.(type) {
+	case map[string]interface{}:
+		c, ok := cur.(map[string]interface{})
+		if !ok || !hasAllKeys(c, o) {
+			break
+		}
+		for k, v := range o {
+			if err := d.diff(v, c[k], docKey(key, k), edits); err != nil {
+				return err
+			}
+		}
+		if len(c) == len(o) {
+			return nil
+		}
+		text, err := d.members(o, c, span)
+		if err != nil {
+			return err
+		}
+		at := span.last
+		if at < 0 {
+			at = span.start + 1
+		}
+		*edits = append(*edits, docEdit{at, at, text})
+		return nil
+	case []interface{}:
+		c, ok := cur.([]interface{})
+		if !ok || len(c) != len(o) {
+			break
+		}
+		for i := range o {
+			if err := d.diff(o[i], c[i], docKey(key, strconv.Itoa(i)), edits); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		if reflect.DeepEqual(orig, cur) {
+			return nil
+		}
+	}
+	text, err := d.encode(cur, lineIndent(d.src, span.start))
+	if err != nil {
+		return err
+	}
+	*edits = append(*edits, docEdit{span.start, span.end, text})
+	return nil
+}
+
+// members renders the keys of cur missing from orig for insertion after the
+// last member of the object at span.
+func (d *Document) members(orig, cur map[string]interface{}, span docSpan) (string, error) {
+	var added []string
+	for k := range cur {
+		if _, ok := orig[k]; !ok {
+			added = append(added, k)
+		}
+	}
+	sort.Strings(added)
+	indent, sep := "", ", "
+	if span.lastKey >= 0 && bytes.IndexByte(d.src[span.start:span.lastKey], '\n') >= 0 {
+		indent = lineIndent(d.src, span.lastKey)
+		sep = ",\n" + indent
+	}
+	var b strings.Builder
+	for i, k := range added {
+		if i > 0 || span.last >= 0 {
+			b.WriteString(sep)
+		}
+		name, err := d.encode(k, "")
+		if err != nil {
+			return "", err
+		}
+		v, err := d.encode(cur[k], indent)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(name + ": " + v)
+	}
+	return b.String(), nil
+}
+
+// encode renders v as JSON for a value whose line starts with prefix.
+func (d *Document) encode(v interface{}, prefix string) (string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent(prefix, d.indent)
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// hasAllKeys reports whether every key of orig is still in cur.
+func hasAllKeys(cur, orig map[string]interface{}) bool {
+	for k := range orig {
+		if _, ok := cur[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// docKey extends the span key of a value with a child key or index. Keys are
+// NUL-prefixed so any map key, including one containing the separator, has
+// its own span.
+func docKey(parent, child string) string {
+	return parent + "\x00" + child
+}
+
+// lineIndent returns the leading whitespace of the line containing pos.
+func lineIndent(src []byte, pos int) string {
+	start := bytes.LastIndexByte(src[:pos], '\n') + 1
+	end := start
+	for end < len(src) && (src[end] == ' ' || src[end] == '\t') {
+		end++
+	}
+	return string(src[start:end])
+}
+
+// docScanner records the span of every value in JSON that is known to be
+// valid, with comments and trailing commas already blanked out.
+type docScanner struct {
+	b     []byte
+	pos   int
+	spans map[string]docSpan
+}
+
+func (s *docScanner) skipSpace() {
+	for s.pos < len(s.b) && isJSONSpace(s.b[s.pos]) {
+		s.pos++
+	}
+}
+
+func (s *docScanner) value(key string) {
+	s.skipSpace()
+	span := docSpan{start: s.pos, last: -1, lastKey: -1}
+	switch s.b[s.pos] {
+	case '{':
+		s.pos++
+		for {
+			s.skipSpace()
+			if s.b[s.pos] == '}' {
+				break
+			}
+			keyStart := s.pos
+			s.str()
+			var name string
+			json.Unmarshal(s.b[keyStart:s.pos], &name)
+			s.skipSpace()
+			s.pos++ // ':'
+			s.value(docKey(key, name))
+			span.last, span.lastKey = s.pos, keyStart
+			s.skipSpace()
+			if s.b[s.pos] == ',' {
+				s.pos++
+			}
+		}
+		s.pos++
+	case '[':
+		s.pos++
+		for i := 0; ; i++ {
+			s.skipSpace()
+			if s.b[s.pos] == ']' {
+				break
+			}
+			s.value(docKey(key, strconv.Itoa(i)))
+			s.skipSpace()
+			if s.b[s.pos] == ',' {
+				s.pos++
+			}
+		}
+		s.pos++
+	case '"':
+		s.str()
+	default:
+		for s.pos < len(s.b) && !isJSONSpace(s.b[s.pos]) && !strings.ContainsRune(",]}", rune(s.b[s.pos])) {
+			s.pos++
+		}
+	}
+	span.end = s.pos
+	s.spans[key] = span
+}
+
+// str skips a string literal starting at the current position.
+func (s *docScanner) str() {
+	for s.pos++; s.b[s.pos] != '"'; s.pos++ {
+		if s.b[s.pos] == '\\' {
+			s.pos++
+		}
+	}
+	s.pos++
+}
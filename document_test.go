@@ -0,0 +1,117 @@
+package config_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+const documentSource = `{
+    // Service settings, edited by ops.
+    "name": "api",
+    "port": 8080, /* public port */
+    "ratio": 1.50,
+    "tags": ["a", "b"],
+    "db": {
+        "host": "db.internal", // primary
+        "pool": {"min": 1, "max": 10},
+    },
+}
+`
+
+func Test_ConfigDocumentRoundTrip(t *testing.T) {
+	doc, err := config.ParseJSONDocument(documentSource)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 8080, doc.MustInt("port"))
+	assert.Equal(t, "db.internal", doc.MustString("db.host"))
+
+	out, err := doc.Bytes()
+	assert.NoError(t, err)
+	assert.Equal(t, documentSource, string(out))
+
+	assert.NoError(t, doc.Set("port", 9090))
+	assert.NoError(t, doc.Set("db.host", "db2.internal"))
+	assert.NoError(t, doc.Set("tags.1", "c"))
+	assert.NoError(t, doc.Set("db.user", "app"))
+	assert.NoError(t, doc.Set("db.pool.idle", 2))
+	out, err = doc.Bytes()
+	assert.NoError(t, err)
+	assert.Equal(t, `{
+    // Service settings, edited by ops.
+    "name": "api",
+    "port": 9090, /* public port */
+    "ratio": 1.50,
+    "tags": ["a", "c"],
+    "db": {
+        "host": "db2.internal", // primary
+        "pool": {"min": 1, "max": 10, "idle": 2},
+        "user": "app",
+    },
+}
+`, string(out))
+}
+
+func Test_ConfigDocumentRewrites(t *testing.T) {
+	doc, err := config.ParseJSONDocument(documentSource)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, doc.Delete("db.pool.min"))
+	assert.NoError(t, doc.Delete("tags.0"))
+	out, err := doc.Bytes()
+	assert.NoError(t, err)
+	assert.Equal(t, `{
+    // Service settings, edited by ops.
+    "name": "api",
+    "port": 8080, /* public port */
+    "ratio": 1.50,
+    "tags": [
+        "b"
+    ],
+    "db": {
+        "host": "db.internal", // primary
+        "pool": {
+            "max": 10
+        },
+    },
+}
+`, string(out))
+
+	reparsed, err := config.ParseJSONC(string(out))
+	assert.NoError(t, err)
+	assert.Equal(t, doc.Raw(), reparsed.Raw())
+}
+
+func Test_ConfigDocumentSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.jsonc")
+	if err := ioutil.WriteFile(path, []byte(documentSource), 0600); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := config.ParseJSONDocumentFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, doc.Set("name", "web"))
+	assert.NoError(t, doc.Save())
+	assert.NoError(t, doc.Set("ratio", 2))
+	assert.NoError(t, doc.Save())
+
+	data, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"name": "web",`)
+	assert.Contains(t, string(data), `"ratio": 2,`)
+	assert.Contains(t, string(data), `"port": 8080, /* public port */`)
+
+	inline, err := config.ParseJSONDocument(`{}`)
+	assert.NoError(t, err)
+	assert.Error(t, inline.Save())
+	assert.NoError(t, inline.Set("a", true))
+	out, err := inline.Bytes()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a": true}`, string(out))
+}
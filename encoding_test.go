@@ -0,0 +1,37 @@
+package config_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigByteOrderMark(t *testing.T) {
+	cfg, err := config.ParseJSONFile("resources/config/bom.json")
+	assert.NoError(t, err)
+	assert.Equal(t, "bom", cfg.MustString("name"))
+
+	data, err := ioutil.ReadFile("resources/config/bom.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = config.ParseJSONReader(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, 8080, cfg.MustInt("port"))
+	cfg, err = config.ParseJSONC(string(data))
+	assert.NoError(t, err)
+	assert.Equal(t, "bom", cfg.MustString("name"))
+
+	for _, path := range []string{"resources/config/utf16le.json", "resources/config/utf16be.json"} {
+		cfg, err := config.ParseJSONFile(path)
+		assert.NoError(t, err, path)
+		assert.Equal(t, "café", cfg.MustString("name"), path)
+		assert.Equal(t, 8080, cfg.MustInt("port"), path)
+	}
+
+	_, err = config.ParseJSON("\xff\xfe{\x00")
+	assert.Error(t, err)
+}
@@ -0,0 +1,172 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// OverrideFromEnv replaces leaf values with environment variables named after
+// their path: the prefix and each segment upper-cased and joined with "_",
+// with list elements addressed by index. With prefix "APP", the leaf
+// "servers.0.host" is overridden by APP_SERVERS_0_HOST. Characters other than
+// letters and digits in keys become "_". Only existing leaves are overridden;
+// the variable is converted to the type of the value it replaces.
+func (c *ConfigImpl) OverrideFromEnv(prefix string) error {
+	defer c.beginWrite()()
+	root := copyValue(c.tree()).(map[string]interface{})
+	if err := overrideEnv(root, envName(prefix)); err != nil {
+		return err
+	}
+	if err := c.commit(root); err != nil {
+		return err
+	}
+	c.noteSources(envSources(root, prefix, c.separator()))
+	return nil
+}
+
+func overrideEnv(node interface{}, name string) error {
+	switch x := node.(type) {
+	case map[string]interface{}:
+		for k, v := range x {
+			child := joinEnv(name, envName(k))
+			replaced, err := overrideEnvValue(v, child)
+			if err != nil {
+				return err
+			}
+			x[k] = replaced
+		}
+	case []interface{}:
+		for i, v := range x {
+			child := joinEnv(name, strconv.Itoa(i))
+			replaced, err := overrideEnvValue(v, child)
+			if err != nil {
+				return err
+			}
+			x[i] = replaced
+		}
+	}
+	return nil
+}
+
+func overrideEnvValue(v interface{}, name string) (interface{}, error) {
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		return v, overrideEnv(v, name)
+	}
+	s, ok := os.LookupEnv(name)
+	if !ok {
+		return v, nil
+	}
+	switch v.(type) {
+	case bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("config: Invalid bool in %s: %q", name, s)
+		}
+		return b, nil
+	case float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("config: Invalid number in %s: %q", name, s)
+		}
+		return f, nil
+	case json.Number:
+		if _, err := strconv.ParseFloat(s, 64); err != nil {
+			return nil, fmt.Errorf("config: Invalid number in %s: %q", name, s)
+		}
+		return json.Number(s), nil
+	}
+	return s, nil
+}
+
+// ToEnv renders every leaf as a NAME=value line, sorted by name, using the
+// same naming as OverrideFromEnv: with prefix "APP", "server.port" becomes
+// APP_SERVER_PORT and "hobbies.0" becomes APP_HOBBIES_0. Numbers are written
+// in their shortest form ("5.1", not "5.100000") and null as an empty value.
+// Values containing anything but letters, digits and _-.,:/@%+= are wrapped
+// in single quotes; an embedded single quote closes the quoting, is written
+// as \' and reopens it. Newlines, double quotes, spaces and "$" thus reach a
+// POSIX shell unchanged.
+func (c *ConfigImpl) ToEnv(prefix string) []string {
+	var lines []string
+	walk(c.tree(), "", defaultSeparator, func(path string, value interface{}) error {
+		name := envName(prefix)
+		for _, seg := range splitPath(path, defaultSeparator) {
+			name = joinEnv(name, envName(seg))
+		}
+		lines = append(lines, name+"="+shellQuote(envValue(value)))
+		return nil
+	})
+	sort.Strings(lines)
+	return lines
+}
+
+func envValue(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+func shellQuote(s string) string {
+	safe := strings.IndexFunc(s, func(r rune) bool {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return false
+		}
+		return !strings.ContainsRune("_-.,:/@%+=", r)
+	}) < 0
+	if safe {
+		return s
+	}
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+func joinEnv(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "_" + name
+}
+
+// envName upper-cases s and replaces anything but letters and digits with "_".
+func envName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, s)
+}
@@ -0,0 +1,78 @@
+package config_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func setenv(t *testing.T, vars map[string]string) func() {
+	for k, v := range vars {
+		if err := os.Setenv(k, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return func() {
+		for k := range vars {
+			os.Unsetenv(k)
+		}
+	}
+}
+
+func Test_ConfigOverrideFromEnv(t *testing.T) {
+	cfg, err := config.ParseJSON(`{
+		"debug": false,
+		"servers": [{"host": "a", "port": 80}, {"host": "b", "port": 81}],
+		"matrix": [[1, 2], [3, 4]],
+		"log": {"level": "info"}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer setenv(t, map[string]string{
+		"APP_DEBUG":          "true",
+		"APP_SERVERS_0_HOST": "override",
+		"APP_SERVERS_1_PORT": "8081",
+		"APP_MATRIX_1_0":     "30",
+		"APP_LOG_LEVEL":      "warn",
+	})()
+
+	assert.NoError(t, cfg.OverrideFromEnv("APP"))
+	assert.Equal(t, true, cfg.MustBool("debug"))
+	assert.Equal(t, "override", cfg.MustString("servers.0.host"))
+	assert.Equal(t, "b", cfg.MustString("servers.1.host"))
+	assert.Equal(t, 8081, cfg.MustInt("servers.1.port"))
+	assert.Equal(t, 30, cfg.MustInt("matrix.1.0"))
+	assert.Equal(t, "warn", cfg.MustString("log.level"))
+
+	defer setenv(t, map[string]string{"APP_SERVERS_0_PORT": "eighty"})()
+	assert.Error(t, cfg.OverrideFromEnv("APP"))
+	assert.Equal(t, 80, cfg.MustInt("servers.0.port"))
+}
+
+func Test_ConfigToEnv(t *testing.T) {
+	cfg, err := config.ParseJSON(`{
+		"server": {"port": 8080, "host": "0.0.0.0"},
+		"height": 5.10,
+		"debug": true,
+		"motd": "it's a\nnew day",
+		"hobbies": ["go", "music"],
+		"empty": null
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []string{
+		"APP_DEBUG=true",
+		"APP_EMPTY=",
+		"APP_HEIGHT=5.1",
+		"APP_HOBBIES_0=go",
+		"APP_HOBBIES_1=music",
+		"APP_MOTD='it'\\''s a\nnew day'",
+		"APP_SERVER_HOST=0.0.0.0",
+		"APP_SERVER_PORT=8080",
+	}, cfg.ToEnv("APP"))
+}
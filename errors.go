@@ -0,0 +1,101 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNotFound is reported when a path does not resolve to a value.
+	ErrNotFound = errors.New("config: not found")
+
+	// ErrWrongType is reported when the value at a path cannot be read as
+	// the requested type.
+	ErrWrongType = errors.New("config: wrong type")
+
+	// ErrInvalidValue is reported when a value has the right type but
+	// violates a constraint such as a range or an allowed set.
+	ErrInvalidValue = errors.New("config: invalid value")
+
+	// ErrSealed is reported when a change would modify a sealed subtree.
+	ErrSealed = errors.New("config: sealed")
+
+	// ErrReadOnly is reported when a frozen config is asked to change.
+	ErrReadOnly = errors.New("config: read-only")
+
+	// ErrInvalidDefault is reported when the default config compiled into
+	// the program does not parse, which is a bug in the program rather than
+	// in the user's configuration.
+	ErrInvalidDefault = errors.New("config: invalid built-in default")
+)
+
+// PathError is returned when a path cannot be read. Error keeps the
+// package's human readable message; FullPath is the path that was requested,
+// FailedAt the prefix of it where resolution stopped, and Reason the sentinel
+// matched by errors.Is. Errors not tied to a lookup leave the paths empty.
+// When a map key is not found but a sibling key is a likely typo of it,
+// Suggestion names that key and the message ends with a "did you mean" hint.
+type PathError struct {
+	FullPath   string
+	FailedAt   string
+	Reason     error
+	Suggestion string
+	msg        string
+}
+
+func newPathError(reason error, format string, args ...interface{}) error {
+	return &PathError{Reason: reason, msg: fmt.Sprintf(format, args...)}
+}
+
+// fetchError is newPathError for a lookup of full that failed at failedAt.
+func fetchError(reason error, full, failedAt, format string, args ...interface{}) error {
+	return &PathError{FullPath: full, FailedAt: failedAt, Reason: reason, msg: fmt.Sprintf(format, args...)}
+}
+
+// unknownKey reports that part is not a key of m while looking up full.
+func unknownKey(m map[string]interface{}, part, full, failedAt string) error {
+	err := &PathError{FullPath: full, FailedAt: failedAt, Reason: ErrNotFound}
+	err.msg = fmt.Sprintf("config: Unknown path at %q", failedAt)
+	if err.Suggestion = suggestKey(m, part); err.Suggestion != "" {
+		err.msg += fmt.Sprintf(" (did you mean %q?)", err.Suggestion)
+	}
+	return err
+}
+
+func (e *PathError) Error() string {
+	return e.msg
+}
+
+func (e *PathError) Unwrap() error {
+	return e.Reason
+}
+
+// wrongType reports that the value x at path cannot be read as the requested
+// type. A null value gets its own message since it is a present value rather
+// than a mistyped one.
+func wrongType(x interface{}, path string) error {
+	if x == nil {
+		return fetchError(ErrWrongType, path, path, "config: Null value at %q", path)
+	}
+	return fetchError(ErrWrongType, path, path, "config: Unknown type at %q", path)
+}
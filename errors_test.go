@@ -0,0 +1,110 @@
+package config_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigPathError(t *testing.T) {
+	cfg, err := config.ParseJSONFile("resources/config/default.conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = cfg.String("nested.1.2.7.0.b")
+	var pe *config.PathError
+	if assert.True(t, errors.As(err, &pe)) {
+		assert.Equal(t, "nested.1.2.7.0.b", pe.FullPath)
+		assert.Equal(t, "nested.1.2.7", pe.FailedAt)
+		assert.Equal(t, config.ErrNotFound, pe.Reason)
+	}
+	assert.EqualError(t, err, `config: Index out of bound at "nested.1.2.7"`)
+	assert.True(t, errors.Is(err, config.ErrNotFound))
+
+	_, err = cfg.Int("clothes.size.inner")
+	if assert.True(t, errors.As(err, &pe)) {
+		assert.Equal(t, "clothes.size.inner", pe.FullPath)
+		assert.Equal(t, "clothes.size.inner", pe.FailedAt)
+		assert.Equal(t, config.ErrWrongType, pe.Reason)
+	}
+
+	_, err = cfg.Int("name")
+	if assert.True(t, errors.As(err, &pe)) {
+		assert.Equal(t, "name", pe.FailedAt)
+		assert.True(t, errors.Is(err, config.ErrWrongType))
+	}
+}
+
+func Test_ConfigPathErrorSuggestion(t *testing.T) {
+	cfg, err := config.ParseJSON(`{"server": {"port": 80, "host": "a"}, "database": {"pool": 5}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = cfg.Int("sever.port")
+	assert.EqualError(t, err, `config: Unknown path at "sever" (did you mean "server"?)`)
+	var pe *config.PathError
+	if assert.True(t, errors.As(err, &pe)) {
+		assert.Equal(t, "server", pe.Suggestion)
+	}
+
+	_, err = cfg.Int("databse.pool")
+	assert.Contains(t, err.Error(), `(did you mean "database"?)`)
+	_, err = cfg.Int("server.address")
+	assert.NotContains(t, err.Error(), "did you mean")
+
+	_, err = cfg.Int("metrics.port")
+	assert.EqualError(t, err, `config: Unknown path at "metrics"`)
+	if assert.True(t, errors.As(err, &pe)) {
+		assert.Equal(t, "", pe.Suggestion)
+	}
+}
+
+func Test_ConfigNullIntermediate(t *testing.T) {
+	cfg, err := config.ParseJSON(`{"pipelines": [null, {"name": "metrics"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "metrics", cfg.MustString("pipelines.1.name"))
+
+	_, err = cfg.String("pipelines.0.name")
+	assert.True(t, errors.Is(err, config.ErrNotFound))
+	assert.EqualError(t, err, `config: Null value at "pipelines.0"`)
+	var pe *config.PathError
+	if assert.True(t, errors.As(err, &pe)) {
+		assert.Equal(t, "pipelines.0.name", pe.FullPath)
+		assert.Equal(t, "pipelines.0", pe.FailedAt)
+	}
+	assert.False(t, cfg.Has("pipelines.0.name"))
+	assert.True(t, cfg.Has("pipelines.0"))
+}
+
+func Test_ConfigStrictKeys(t *testing.T) {
+	const doc = `{"port": 80, "db": {"host": "a", "replicas": [{"": 1, "x": 2}, {"host": "b", "port": 1, "port": 2}]}}`
+
+	cfg, err := config.ParseJSON(doc)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, cfg.MustInt("db.replicas.1.port"))
+
+	_, err = config.ParseJSON(doc, config.StrictKeys())
+	assert.True(t, errors.Is(err, config.ErrInvalidValue))
+	assert.EqualError(t, err, `config: Duplicate key "port" at "db.replicas.1.port"`)
+
+	_, err = config.ParseJSON(`{"a": 1, "b": {"a": 2}, "c": [{"a": 3}, {"a": 4}]}`, config.StrictKeys())
+	assert.NoError(t, err)
+	_, err = config.ParseJSONC("{\"a\": 1, // first\n \"a\": 2}", config.StrictKeys())
+	assert.EqualError(t, err, `config: Duplicate key "a" at "a"`)
+	_, err = config.ParseJSON(`{"a.b": 1, "a.b": 2}`, config.StrictKeys())
+	var pathErr *config.PathError
+	assert.True(t, errors.As(err, &pathErr))
+	assert.Equal(t, `a\.b`, pathErr.FullPath)
+	_, err = config.ParseJSON(`{"log.cfg": {"level": 1, "level": 2}}`, config.StrictKeys())
+	assert.True(t, errors.As(err, &pathErr))
+	assert.Equal(t, `log\.cfg.level`, pathErr.FullPath)
+	_, err = config.ParseJSON(`{"a": `, config.StrictKeys())
+	assert.Error(t, err)
+}
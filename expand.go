@@ -0,0 +1,87 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"fmt"
+	"math"
+)
+
+// maxRangeLen bounds the size of an expanded $range so a typo in the
+// configuration cannot exhaust memory.
+const maxRangeLen = 1 << 20
+
+// expandDirective replaces computed values with their expansion. A map whose
+// only key is "$range" describes a numeric sequence:
+//
+//	{"$range": {"start": 1, "end": 5, "step": 2}} => [1, 3, 5]
+//
+// Both bounds are inclusive. The step defaults to 1 (or -1 when end is below
+// start). Any other value is returned unchanged.
+func expandDirective(v interface{}, path string) (interface{}, error) {
+	m, ok := v.(map[string]interface{})
+	if !ok || len(m) != 1 {
+		return v, nil
+	}
+	spec, ok := m["$range"]
+	if !ok {
+		return v, nil
+	}
+	return expandRange(spec, path)
+}
+
+func expandRange(spec interface{}, path string) (interface{}, error) {
+	m, ok := spec.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("config: Invalid $range at %q", path)
+	}
+	start, ok := toFloat64(m["start"])
+	if !ok {
+		return nil, fmt.Errorf("config: Invalid $range start at %q", path)
+	}
+	end, ok := toFloat64(m["end"])
+	if !ok {
+		return nil, fmt.Errorf("config: Invalid $range end at %q", path)
+	}
+	step := 1.0
+	if end < start {
+		step = -1
+	}
+	if s, found := m["step"]; found {
+		if step, ok = toFloat64(s); !ok || step == 0 {
+			return nil, fmt.Errorf("config: Invalid $range step at %q", path)
+		}
+	}
+	if (end-start)/step < 0 {
+		return []interface{}{}, nil
+	}
+	// Count the steps up front: adding step to a large float can leave it
+	// unchanged, so a loop accumulating v might never reach end.
+	n := math.Floor((end - start) / step)
+	if n >= maxRangeLen {
+		return nil, fmt.Errorf("config: $range too large at %q", path)
+	}
+	out := make([]interface{}, 0, int(n)+1)
+	for i := 0; float64(i) <= n; i++ {
+		out = append(out, start+float64(i)*step)
+	}
+	return out, nil
+}
@@ -0,0 +1,38 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigRange(t *testing.T) {
+	cfg, err := config.ParseJSON(`{
+		"ports": {"$range": {"start": 1, "end": 5}},
+		"shards": {"$range": {"start": 0, "end": 10, "step": 4}},
+		"bad": {"$range": {"start": 0, "end": 10, "step": 0}},
+		"large": {"$range": {"start": 1e17, "end": 1.0000000000001e17, "step": 1}}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ports, err := cfg.List("ports")
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{1.0, 2.0, 3.0, 4.0, 5.0}, ports)
+	assert.Equal(t, 3, cfg.MustInt("ports.2"))
+
+	shards, err := cfg.List("shards")
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{0.0, 4.0, 8.0}, shards)
+
+	_, err = cfg.List("bad")
+	assert.Error(t, err)
+
+	large, err := cfg.List("large")
+	assert.NoError(t, err)
+	assert.Len(t, large, 10001)
+	assert.Equal(t, 1e17, large[0])
+	assert.Equal(t, 1.0000000000001e17, large[10000])
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"strings"
+)
+
+// FirstOf returns the value at the first of paths that resolves, so a key
+// can be read under its current name and the names it had before:
+//
+//	addr, err := cfg.FirstOf("server.addr", "server.address", "addr")
+//
+// A path present in the config is preferred over an earlier one that only has
+// a global default. When none resolves the ErrNotFound error lists every path
+// tried.
+func (c *ConfigImpl) FirstOf(paths ...string) (interface{}, error) {
+	path, err := c.firstOf(paths)
+	if err != nil {
+		return nil, err
+	}
+	return c.Get(path)
+}
+
+// StringFirstOf is String for the first of paths that resolves. A value of
+// the wrong type there is an error; the remaining paths are not tried.
+func (c *ConfigImpl) StringFirstOf(paths ...string) (string, error) {
+	path, err := c.firstOf(paths)
+	if err != nil {
+		return "", err
+	}
+	return c.String(path)
+}
+
+// IntFirstOf is Int for the first of paths that resolves.
+func (c *ConfigImpl) IntFirstOf(paths ...string) (int, error) {
+	path, err := c.firstOf(paths)
+	if err != nil {
+		return -1, err
+	}
+	return c.Int(path)
+}
+
+// BoolFirstOf is Bool for the first of paths that resolves.
+func (c *ConfigImpl) BoolFirstOf(paths ...string) (bool, error) {
+	path, err := c.firstOf(paths)
+	if err != nil {
+		return false, err
+	}
+	return c.Bool(path)
+}
+
+// FloatFirstOf is Float for the first of paths that resolves.
+func (c *ConfigImpl) FloatFirstOf(paths ...string) (float64, error) {
+	path, err := c.firstOf(paths)
+	if err != nil {
+		return -1, err
+	}
+	return c.Float(path)
+}
+
+// firstOf returns the first of paths that resolves. As with Get, a value in
+// the config beats a global default, so the paths are first looked up in the
+// config alone and global defaults only count when none of them is present.
+func (c *ConfigImpl) firstOf(paths []string) (string, error) {
+	for _, path := range paths {
+		if _, err := c.lookup(path); err == nil {
+			return path, nil
+		}
+	}
+	var tried []string
+	for _, path := range paths {
+		_, err := c.Get(path)
+		if err == nil {
+			return path, nil
+		}
+		tried = append(tried, err.Error())
+	}
+	if len(tried) == 0 {
+		return "", newPathError(ErrNotFound, "config: FirstOf requires at least one path")
+	}
+	return "", newPathError(ErrNotFound, "config: none of %q resolve:\n  %s", paths, strings.Join(tried, "\n  "))
+}
@@ -0,0 +1,59 @@
+package config_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigFirstOf(t *testing.T) {
+	cfg, err := config.ParseJSON(`{"server": {"address": ":8080", "port": 8080, "tls": true, "ratio": 0.5}, "addr": ":9090"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	x, err := cfg.FirstOf("server.addr", "server.address", "addr")
+	assert.NoError(t, err)
+	assert.Equal(t, ":8080", x)
+	s, err := cfg.StringFirstOf("server.addr", "addr")
+	assert.NoError(t, err)
+	assert.Equal(t, ":9090", s)
+	i, err := cfg.IntFirstOf("server.listen_port", "server.port")
+	assert.NoError(t, err)
+	assert.Equal(t, 8080, i)
+	b, err := cfg.BoolFirstOf("tls", "server.tls")
+	assert.NoError(t, err)
+	assert.True(t, b)
+	f, err := cfg.FloatFirstOf("server.ratio", "ratio")
+	assert.NoError(t, err)
+	assert.Equal(t, 0.5, f)
+
+	_, err = cfg.IntFirstOf("server.address", "server.port")
+	assert.True(t, errors.Is(err, config.ErrWrongType))
+
+	_, err = cfg.FirstOf("server.addr", "address")
+	assert.True(t, errors.Is(err, config.ErrNotFound))
+	assert.Contains(t, err.Error(), `none of ["server.addr" "address"] resolve`)
+	assert.Contains(t, err.Error(), `Unknown path at "server.addr"`)
+	_, err = cfg.FirstOf()
+	assert.True(t, errors.Is(err, config.ErrNotFound))
+}
+
+func Test_ConfigFirstOfGlobalDefault(t *testing.T) {
+	defer config.ResetGlobalDefaults()
+	config.SetGlobalDefault("server.address", "0.0.0.0:80")
+	cfg, err := config.ParseJSON(`{"server": {"addr": "127.0.0.1:8080"}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := cfg.StringFirstOf("server.address", "server.addr")
+	assert.NoError(t, err)
+	assert.Equal(t, "127.0.0.1:8080", s)
+
+	s, err = cfg.StringFirstOf("server.address", "server.listen")
+	assert.NoError(t, err)
+	assert.Equal(t, "0.0.0.0:80", s)
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// BindFlags makes config values the defaults of command line flags. mapping
+// maps flag names to config paths; call BindFlags after defining the flags
+// and before fs.Parse, so a flag given on the command line still wins. The
+// config value must suit the flag's type: bool, int, int64, uint, uint64,
+// float64, string or time.Duration (from a string such as "5s"). Paths
+// absent from the config leave the flag untouched. Unknown flags and type
+// mismatches are errors, and no flag is changed when any entry fails.
+func (c *ConfigImpl) BindFlags(fs *flag.FlagSet, mapping map[string]string) error {
+	names := make([]string, 0, len(mapping))
+	for name := range mapping {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	values := map[*flag.Flag]string{}
+	for _, name := range names {
+		path := mapping[name]
+		f := fs.Lookup(name)
+		if f == nil {
+			return fmt.Errorf("config: Unknown flag %q for %q", name, path)
+		}
+		if !c.Has(path) {
+			continue
+		}
+		s, err := c.flagValue(f, path)
+		if err != nil {
+			return err
+		}
+		values[f] = s
+	}
+	for f, s := range values {
+		if err := f.Value.Set(s); err != nil {
+			return fmt.Errorf("config: setting flag %q: %w", f.Name, err)
+		}
+		f.DefValue = s
+	}
+	return nil
+}
+
+// flagValue renders the value at path as text for the flag, checking it
+// against the flag's type.
+func (c *ConfigImpl) flagValue(f *flag.Flag, path string) (string, error) {
+	getter, ok := f.Value.(flag.Getter)
+	if !ok {
+		return c.String(path)
+	}
+	var err error
+	switch getter.Get().(type) {
+	case bool:
+		var b bool
+		if b, err = c.Bool(path); err == nil {
+			return strconv.FormatBool(b), nil
+		}
+	case int, int64:
+		var i int64
+		if i, err = c.Int64(path); err == nil {
+			return strconv.FormatInt(i, 10), nil
+		}
+	case uint, uint64:
+		var u uint64
+		if u, err = c.Uint64(path); err == nil {
+			return strconv.FormatUint(u, 10), nil
+		}
+	case float64:
+		var f float64
+		if f, err = c.Float(path); err == nil {
+			return strconv.FormatFloat(f, 'g', -1, 64), nil
+		}
+	case time.Duration:
+		var s string
+		if s, err = c.String(path); err == nil {
+			if _, perr := time.ParseDuration(s); perr != nil {
+				err = newPathError(ErrWrongType, "config: Invalid duration %q at %q", s, path)
+			} else {
+				return s, nil
+			}
+		}
+	default:
+		return c.String(path)
+	}
+	return "", fmt.Errorf("config: flag %q: %w", f.Name, err)
+}
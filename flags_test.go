@@ -0,0 +1,61 @@
+package config_test
+
+import (
+	"errors"
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigBindFlags(t *testing.T) {
+	cfg, err := config.ParseJSON(`{
+		"server": {"host": "example.com", "port": 8080, "timeout": "5s"},
+		"debug": true,
+		"ratio": 0.75,
+		"workers": 4
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fs := flag.NewFlagSet("app", flag.ContinueOnError)
+	host := fs.String("host", "localhost", "")
+	port := fs.Int("port", 80, "")
+	debug := fs.Bool("debug", false, "")
+	ratio := fs.Float64("ratio", 1, "")
+	workers := fs.Uint("workers", 1, "")
+	timeout := fs.Duration("timeout", time.Second, "")
+	retries := fs.Int("retries", 3, "")
+
+	assert.NoError(t, cfg.BindFlags(fs, map[string]string{
+		"host":    "server.host",
+		"port":    "server.port",
+		"debug":   "debug",
+		"ratio":   "ratio",
+		"workers": "workers",
+		"timeout": "server.timeout",
+		"retries": "missing.retries",
+	}))
+	assert.NoError(t, fs.Parse([]string{"-port", "9090"}))
+
+	assert.Equal(t, "example.com", *host)
+	assert.Equal(t, 9090, *port)
+	assert.True(t, *debug)
+	assert.Equal(t, 0.75, *ratio)
+	assert.Equal(t, uint(4), *workers)
+	assert.Equal(t, 5*time.Second, *timeout)
+	assert.Equal(t, 3, *retries)
+	assert.Equal(t, "example.com", fs.Lookup("host").DefValue)
+
+	fs = flag.NewFlagSet("app", flag.ContinueOnError)
+	port = fs.Int("port", 80, "")
+	err = cfg.BindFlags(fs, map[string]string{"port": "server.host"})
+	assert.True(t, errors.Is(err, config.ErrWrongType))
+	assert.Equal(t, 80, *port)
+
+	err = cfg.BindFlags(fs, map[string]string{"nope": "debug"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Flatten returns every leaf of the config keyed by its full path, e.g.
+// {"clothes.pants.waist": 32.0, "hobbies.0": "skateboard"}. Empty maps and
+// lists have no leaves and are therefore omitted.
+func (c *ConfigImpl) Flatten() map[string]interface{} {
+	out := map[string]interface{}{}
+	c.Walk(func(path string, value interface{}) error {
+		out[path] = value
+		return nil
+	})
+	return out
+}
+
+// Paths returns the full path of every leaf in the config in sorted order,
+// the keys of Flatten.
+func (c *ConfigImpl) Paths() []string {
+	return c.PathsUnder("")
+}
+
+// PathsUnder is Paths for the subtree at prefix. A prefix naming a leaf
+// returns just that path, and one that does not resolve returns nil.
+func (c *ConfigImpl) PathsUnder(prefix string) []string {
+	node, err := c.lookup(prefix)
+	if err != nil {
+		return nil
+	}
+	var paths []string
+	walk(node, c.join(c.split(prefix)...), c.separator(), func(path string, _ interface{}) error {
+		paths = append(paths, path)
+		return nil
+	})
+	sort.Strings(paths)
+	return paths
+}
+
+// Unflatten is the inverse of Flatten: it rebuilds the nested tree from a map
+// of dotted paths to leaf values. Maps whose keys are exactly "0" to "n-1"
+// become lists. A key that is used both as a leaf and as the prefix of
+// another key is an error.
+func Unflatten(m map[string]interface{}) (Config, error) {
+	root, err := unflatten(m, defaultSeparator)
+	if err != nil {
+		return nil, err
+	}
+	return &ConfigImpl{opts: newOptions(nil), root: root, origin: SourceInline}, nil
+}
+
+// NewFromFlatStrings builds a config from string values keyed by dotted
+// path, such as the data of a Kubernetes ConfigMap. Values are coerced as in
+// properties files: "true" and "false" become bools and decimal numbers,
+// including "1.0" and "0.50", become numbers, while anything else, such as
+// hex, values with underscores and integers with a leading zero like "0755",
+// stays a string. As with Unflatten, a key used both as a leaf and as a
+// prefix is an error.
+func NewFromFlatStrings(m map[string]string, opts ...Option) (Config, error) {
+	flat := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		flat[k] = coerceScalar(v)
+	}
+	o := newOptions(opts)
+	root, err := unflatten(flat, o.separator())
+	if err != nil {
+		return nil, err
+	}
+	return newParsed(root, o)
+}
+
+func unflatten(m map[string]interface{}, sep string) (map[string]interface{}, error) {
+	paths := make([]string, 0, len(m))
+	for p := range m {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	root := map[string]interface{}{}
+	for _, p := range paths {
+		parts := splitPath(p, sep)
+		node := root
+		for i, part := range parts[:len(parts)-1] {
+			child, exists := node[part]
+			if !exists {
+				next := map[string]interface{}{}
+				node[part] = next
+				node = next
+				continue
+			}
+			next, ok := child.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("config: Conflicting path %q: %q is a leaf", p, joinSegments(parts[:i+1], sep))
+			}
+			node = next
+		}
+		last := parts[len(parts)-1]
+		if _, ok := node[last]; ok {
+			return nil, fmt.Errorf("config: Conflicting path %q: %q has children", p, p)
+		}
+		node[last] = m[p]
+	}
+	return listify(root).(map[string]interface{}), nil
+}
+
+// listify turns maps keyed "0".."n-1" into lists, recursively.
+func listify(v interface{}) interface{} {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+	for k, e := range m {
+		m[k] = listify(e)
+	}
+	if len(m) == 0 {
+		return m
+	}
+	list := make([]interface{}, len(m))
+	for k, e := range m {
+		ix, err := strconv.Atoi(k)
+		if err != nil || ix < 0 || ix >= len(m) || strconv.Itoa(ix) != k {
+			return m
+		}
+		list[ix] = e
+	}
+	return list
+}
@@ -0,0 +1,127 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigFlatten(t *testing.T) {
+	cfg, err := config.ParseJSONFile("resources/config/default.conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flat := cfg.Flatten()
+	assert.Len(t, flat, 21)
+	assert.Equal(t, 32.0, flat["clothes.pants.waist"])
+	assert.Equal(t, "skateboard", flat["hobbies.0"])
+	assert.Equal(t, "c", flat["nested.1.2.3.0.b"])
+
+	back, err := config.Unflatten(flat)
+	assert.NoError(t, err)
+	assert.Equal(t, flat, back.Flatten())
+	assert.Equal(t, cfg.MustList("hobbies"), back.MustList("hobbies"))
+	assert.Equal(t, cfg.MustMap("clothes"), back.MustMap("clothes"))
+	assert.Equal(t, "c", back.MustString("nested.1.2.3.0.b"))
+}
+
+func Test_ConfigUnflattenConflict(t *testing.T) {
+	_, err := config.Unflatten(map[string]interface{}{
+		"server":      "localhost",
+		"server.port": 8080.0,
+	})
+	assert.Error(t, err)
+
+	_, err = config.Unflatten(map[string]interface{}{
+		"server":      nil,
+		"server.port": 8080.0,
+	})
+	assert.Error(t, err)
+
+	cfg, err := config.Unflatten(map[string]interface{}{
+		"log\\.level": "debug",
+		"ports.1":     81.0,
+		"ports.0":     80.0,
+		"codes.1":     "one",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "debug", cfg.MustString("log\\.level"))
+	assert.Equal(t, []interface{}{80.0, 81.0}, cfg.MustList("ports"))
+	assert.Equal(t, map[string]interface{}{"1": "one"}, cfg.MustMap("codes"))
+}
+
+func Test_ConfigNewFromFlatStrings(t *testing.T) {
+	cfg, err := config.NewFromFlatStrings(map[string]string{
+		"server.host":  "api.internal",
+		"server.port":  "8080",
+		"server.tls":   "true",
+		"ratio":        "0.25",
+		"hosts.0":      "a",
+		"hosts.1":      "b",
+		"build.commit": "0x1f",
+		"file.mode":    "0755",
+		"app.version":  "1.10",
+		"agent":        "007",
+		"sampling":     "0.50",
+		"scale":        "1.0",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "api.internal", cfg.MustString("server.host"))
+	assert.Equal(t, 8080, cfg.MustInt("server.port"))
+	assert.True(t, cfg.MustBool("server.tls"))
+	assert.Equal(t, 0.25, cfg.MustFloat("ratio"))
+	assert.Equal(t, []interface{}{"a", "b"}, cfg.MustList("hosts"))
+	assert.Equal(t, "0x1f", cfg.MustString("build.commit"))
+	assert.Equal(t, "0755", cfg.MustString("file.mode"))
+	assert.Equal(t, 1.1, cfg.MustFloat("app.version"))
+	assert.Equal(t, "007", cfg.MustString("agent"))
+	assert.Equal(t, 0.5, cfg.MustFloat("sampling"))
+	assert.Equal(t, 1.0, cfg.MustFloat("scale"))
+
+	_, err = config.NewFromFlatStrings(map[string]string{
+		"server":      "localhost",
+		"server.port": "8080",
+	})
+	assert.Error(t, err)
+}
+
+func Test_ConfigPaths(t *testing.T) {
+	cfg, err := config.ParseJSONFile("resources/config/default.conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []string{
+		"age",
+		"clothes.pants.height",
+		"clothes.pants.waist",
+		"clothes.size",
+		"debug",
+		"env",
+		"height",
+		"hobbies.0",
+		"hobbies.1",
+		"hobbies.2",
+		"hobbies.3",
+		"name",
+		"nested.0",
+		"nested.1.0",
+		"nested.1.1",
+		"nested.1.2.0",
+		"nested.1.2.1",
+		"nested.1.2.2",
+		"nested.1.2.3.0.a",
+		"nested.1.2.3.0.b",
+		"single",
+	}, cfg.Paths())
+	assert.Len(t, cfg.Paths(), len(cfg.Flatten()))
+
+	assert.Equal(t, []string{"clothes.pants.height", "clothes.pants.waist", "clothes.size"}, cfg.PathsUnder("clothes"))
+	assert.Equal(t, []string{"nested.1.2.3.0.a", "nested.1.2.3.0.b"}, cfg.PathsUnder("nested.1.2.3"))
+	assert.Equal(t, []string{"age"}, cfg.PathsUnder("age"))
+	assert.Nil(t, cfg.PathsUnder("missing"))
+}
@@ -0,0 +1,264 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// redacted replaces sensitive values in formatted output.
+const redacted = "***"
+
+// defaultRedactPatterns are the key fragments masked when the config is
+// formatted with the fmt package.
+var defaultRedactPatterns = []string{"password", "passwd", "secret", "token", "apikey", "api_key", "private_key", "credential"}
+
+// WithRedaction sets the key fragments whose values are masked when the
+// config is formatted with %v, %+v, %s or %#v. Matching is case-insensitive
+// on the key name. Calling it without patterns disables redaction.
+func WithRedaction(patterns ...string) Option {
+	return func(o *options) {
+		o.redact = make([]string, len(patterns))
+		for i, p := range patterns {
+			o.redact[i] = strings.ToLower(p)
+		}
+	}
+}
+
+// Format implements fmt.Formatter. Since String is the string getter, the
+// config cannot be a fmt.Stringer; Format renders the tree compactly with
+// sorted keys instead, masking values whose key matches a redaction pattern.
+func (c *ConfigImpl) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('#') {
+		fmt.Fprint(f, c.GoString())
+		return
+	}
+	fmt.Fprint(f, c.redactedText())
+}
+
+// GoString implements fmt.GoStringer with the same redaction as Format.
+func (c *ConfigImpl) GoString() string {
+	return "&config.ConfigImpl{" + c.redactedText() + "}"
+}
+
+func (c *ConfigImpl) redactedText() string {
+	patterns := c.opts.redact
+	if patterns == nil {
+		patterns = defaultRedactPatterns
+	}
+	var buf bytes.Buffer
+	render(&buf, c.tree(), func(key string) bool {
+		key = strings.ToLower(key)
+		for _, p := range patterns {
+			if p != "" && strings.Contains(key, p) {
+				return true
+			}
+		}
+		return false
+	})
+	return buf.String()
+}
+
+// MarshalJSON encodes the config's data as a JSON object, so a config can be
+// embedded in other JSON output. Annotations and other metadata are left
+// out.
+func (c *ConfigImpl) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.tree())
+}
+
+// Dump renders the config as indented JSON with sorted keys for support
+// output. The value at each of the given paths, a leaf or a whole subtree, is
+// replaced with "***"; paths that do not resolve are ignored. Values JSON
+// cannot represent are written as quoted strings and a map or list that
+// contains itself as "<cycle>", as in Render, so Dump never fails.
+func (c *ConfigImpl) Dump(redact ...string) string {
+	root := copyAcyclic(c.tree(), map[uintptr]bool{})
+	for _, path := range redact {
+		maskPath(root, c.split(path))
+	}
+	var compact, out bytes.Buffer
+	render(&compact, root, func(string) bool { return false })
+	if err := json.Indent(&out, compact.Bytes(), "", "  "); err != nil {
+		return compact.String()
+	}
+	return out.String()
+}
+
+// maskPath replaces the value at parts with the redaction marker.
+func maskPath(node interface{}, parts []string) {
+	for i, part := range parts {
+		last := i == len(parts)-1
+		switch x := node.(type) {
+		case map[string]interface{}:
+			v, ok := x[part]
+			if !ok {
+				return
+			}
+			if last {
+				x[part] = redacted
+				return
+			}
+			node = v
+		case []interface{}:
+			ix, err := strconv.Atoi(part)
+			if err != nil || ix < 0 || ix >= len(x) {
+				return
+			}
+			if last {
+				x[ix] = redacted
+				return
+			}
+			node = x[ix]
+		default:
+			return
+		}
+	}
+}
+
+// Render returns the whole tree as compact JSON with sorted keys and nothing
+// redacted. It plays the role of String, which is taken by the string getter;
+// use %v or Dump for output that may reach logs. A map or list that contains
+// itself, which only Go code can build, is rendered as "<cycle>" where it
+// recurs.
+func (c *ConfigImpl) Render() string {
+	var buf bytes.Buffer
+	render(&buf, c.tree(), func(string) bool { return false })
+	return buf.String()
+}
+
+// render writes v as compact JSON with sorted keys, replacing the value of
+// every key for which redact returns true.
+func render(buf *bytes.Buffer, v interface{}, redact func(key string) bool) {
+	renderNode(buf, v, redact, map[uintptr]bool{})
+}
+
+// cycleMarker replaces a map or list inside itself in formatted output.
+const cycleMarker = "<cycle>"
+
+// enterNode records that the map or list v is being visited, reporting
+// whether it already was, in which case v contains itself. The returned
+// function ends the visit.
+func enterNode(v interface{}, active map[uintptr]bool) (leave func(), cycle bool) {
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		if id := reflect.ValueOf(v).Pointer(); id != 0 {
+			if active[id] {
+				return nil, true
+			}
+			active[id] = true
+			return func() { delete(active, id) }, false
+		}
+	}
+	return func() {}, false
+}
+
+// copyAcyclic is copyValue for a tree that may contain itself, which only Go
+// code can build through Raw: as in render, a map or list that recurs inside
+// itself is replaced with the cycle marker.
+func copyAcyclic(v interface{}, active map[uintptr]bool) interface{} {
+	leave, cycle := enterNode(v, active)
+	if cycle {
+		return cycleMarker
+	}
+	defer leave()
+	switch x := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(x))
+		for k, e := range x {
+			m[k] = copyAcyclic(e, active)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(x))
+		for i, e := range x {
+			l[i] = copyAcyclic(e, active)
+		}
+		return l
+	}
+	return v
+}
+
+// writeString writes s as a JSON string without escaping HTML characters.
+// strconv.Quote is not used since Go escapes such as \x01 are not valid
+// JSON.
+func writeString(buf *bytes.Buffer, s string) {
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	buf.Truncate(buf.Len() - 1) // Encode ends the value with a newline
+}
+
+// renderNode is render tracking the maps and lists being written in active,
+// so that a cycle ends in a marker rather than endless recursion.
+func renderNode(buf *bytes.Buffer, v interface{}, redact func(key string) bool, active map[uintptr]bool) {
+	leave, cycle := enterNode(v, active)
+	if cycle {
+		writeString(buf, cycleMarker)
+		return
+	}
+	defer leave()
+	switch x := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		buf.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeString(buf, k)
+			buf.WriteByte(':')
+			if redact(k) {
+				writeString(buf, redacted)
+				continue
+			}
+			renderNode(buf, x[k], redact, active)
+		}
+		buf.WriteByte('}')
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, e := range x {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			renderNode(buf, e, redact, active)
+		}
+		buf.WriteByte(']')
+	case string:
+		writeString(buf, x)
+	default:
+		b, err := json.Marshal(x)
+		if err != nil {
+			writeString(buf, fmt.Sprint(x))
+			return
+		}
+		buf.Write(b)
+	}
+}
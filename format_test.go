@@ -0,0 +1,87 @@
+package config_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigFormatRedacts(t *testing.T) {
+	data := `{"database": {"host": "db", "password": "hunter2"}, "api_token": "abc", "port": 5432}`
+	cfg, err := config.ParseJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"api_token":"***","database":{"host":"db","password":"***"},"port":5432}`
+	assert.Equal(t, want, fmt.Sprintf("%v", cfg))
+	assert.Equal(t, want, fmt.Sprintf("%+v", cfg))
+	assert.Equal(t, want, fmt.Sprintf("%s", cfg))
+	assert.Equal(t, "&config.ConfigImpl{"+want+"}", fmt.Sprintf("%#v", cfg))
+
+	cfg, err = config.ParseJSON(data, config.WithRedaction("HOST"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, `{"api_token":"abc","database":{"host":"***","password":"hunter2"},"port":5432}`, fmt.Sprintf("%v", cfg))
+}
+
+func Test_ConfigDump(t *testing.T) {
+	cfg, err := config.ParseJSON(`{
+		"database": {"host": "db", "password": "hunter2", "port": 5432},
+		"tokens": ["a", "b"],
+		"tls": {"key": "k", "cert": "c"}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{
+  "database": {
+    "host": "db",
+    "password": "***",
+    "port": 5432
+  },
+  "tls": "***",
+  "tokens": [
+    "a",
+    "***"
+  ]
+}`
+	assert.Equal(t, want, cfg.Dump("database.password", "tls", "tokens.1", "missing.path"))
+	assert.Equal(t, "hunter2", cfg.MustString("database.password"))
+
+	odd, err := config.Unflatten(map[string]interface{}{"ch": make(chan int), "f": func() {}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotPanics(t, func() { odd.Dump() })
+
+	raw := cfg.Raw()
+	raw["self"] = raw
+	raw["ctl\x01"] = "bell\x07"
+	var dump string
+	assert.NotPanics(t, func() { dump = cfg.Dump("tls") })
+	assert.Contains(t, dump, `"self": "<cycle>"`)
+	assert.True(t, json.Valid([]byte(dump)))
+	assert.True(t, json.Valid([]byte(cfg.Render())))
+}
+
+func Test_ConfigRender(t *testing.T) {
+	cfg, err := config.ParseJSON(`{"b": [1, {"z": null, "y": "x"}], "a": {"password": "hunter2"}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, `{"a":{"password":"hunter2"},"b":[1,{"y":"x","z":null}]}`, cfg.Render())
+
+	shared := map[string]interface{}{"n": 1.0}
+	raw := cfg.Raw()
+	raw["left"], raw["right"] = shared, shared
+	shared["self"] = shared
+	assert.Equal(t, `{"a":{"password":"hunter2"},"b":[1,{"y":"x","z":null}],`+
+		`"left":{"n":1,"self":"<cycle>"},"right":{"n":1,"self":"<cycle>"}}`, cfg.Render())
+	assert.NotPanics(t, func() { _ = fmt.Sprintf("%v", cfg) })
+}
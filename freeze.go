@@ -0,0 +1,96 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+// frozenConfig is the read-only view returned by Freeze. It shares the data
+// of the config it wraps; every method that would change that data fails
+// with ErrReadOnly.
+type frozenConfig struct {
+	*ConfigImpl
+}
+
+// Freeze returns a read-only view of the config for code that must not
+// change it after startup. Getters work as usual, while Set, Delete, Append,
+// ApplyDefaults, Extend, ApplyPatch, OverrideFromEnv, ReloadFile,
+// RenderTemplates, ResolveReferences and Transaction fail with ErrReadOnly,
+// and the restore function of Snapshot does nothing. The view shares the
+// data without copying, so it still reflects changes made through the
+// original config; Raw exposes the shared map and must not be modified
+// either.
+func (c *ConfigImpl) Freeze() Config {
+	return frozenConfig{c}
+}
+
+func readOnly(op string) error {
+	return newPathError(ErrReadOnly, "config: %s on a frozen config", op)
+}
+
+func (f frozenConfig) Freeze() Config {
+	return f
+}
+
+func (f frozenConfig) Set(string, interface{}) error {
+	return readOnly("Set")
+}
+
+func (f frozenConfig) Delete(string) error {
+	return readOnly("Delete")
+}
+
+func (f frozenConfig) Append(string, ...interface{}) error {
+	return readOnly("Append")
+}
+
+func (f frozenConfig) ApplyDefaults() error {
+	return readOnly("ApplyDefaults")
+}
+
+func (f frozenConfig) RenderTemplates() error {
+	return readOnly("RenderTemplates")
+}
+
+func (f frozenConfig) ResolveReferences() error {
+	return readOnly("ResolveReferences")
+}
+
+func (f frozenConfig) Extend(Config) (Config, error) {
+	return nil, readOnly("Extend")
+}
+
+func (f frozenConfig) ApplyPatch(map[string]interface{}) error {
+	return readOnly("ApplyPatch")
+}
+
+func (f frozenConfig) OverrideFromEnv(string) error {
+	return readOnly("OverrideFromEnv")
+}
+
+func (f frozenConfig) ReloadFile(string) error {
+	return readOnly("ReloadFile")
+}
+
+func (f frozenConfig) Transaction(func(Config) error) error {
+	return readOnly("Transaction")
+}
+
+func (f frozenConfig) Snapshot() func() {
+	return func() {}
+}
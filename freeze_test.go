@@ -0,0 +1,40 @@
+package config_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigFreeze(t *testing.T) {
+	cfg, err := config.ParseJSON(`{"server": {"port": 8080}, "name": "api"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	frozen := cfg.Freeze()
+
+	assert.Equal(t, 8080, frozen.MustInt("server.port"))
+	assert.Equal(t, "api", frozen.MustString("name"))
+	keys, err := frozen.Keys("")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"name", "server"}, keys)
+
+	assert.True(t, errors.Is(frozen.Set("name", "x"), config.ErrReadOnly))
+	assert.True(t, errors.Is(frozen.Delete("name"), config.ErrReadOnly))
+	_, err = frozen.Extend(cfg)
+	assert.True(t, errors.Is(err, config.ErrReadOnly))
+	assert.True(t, errors.Is(frozen.ApplyPatch(map[string]interface{}{"name": nil}), config.ErrReadOnly))
+	assert.True(t, errors.Is(frozen.Transaction(func(config.Config) error { return nil }), config.ErrReadOnly))
+	assert.EqualError(t, frozen.Set("name", "x"), "config: Set on a frozen config")
+	assert.Equal(t, "api", frozen.MustString("name"))
+
+	assert.NoError(t, cfg.Set("name", "changed"))
+	assert.Equal(t, "changed", frozen.MustString("name"))
+
+	merged, err := frozen.Merge()
+	assert.NoError(t, err)
+	assert.NoError(t, merged.Set("name", "copy"))
+	assert.Equal(t, "changed", frozen.MustString("name"))
+}
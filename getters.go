@@ -0,0 +1,383 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// URL returns the absolute URL at the dotted path. The value must be a string
+// with a scheme; opaque forms are rejected, so a bare "host:port", which
+// url.Parse would read as scheme "host", is an error rather than a surprise.
+func (c *ConfigImpl) URL(path string) (*url.URL, error) {
+	s, err := c.String(path)
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, newPathError(ErrInvalidValue, "config: Invalid URL at %q: %v", path, err)
+	}
+	if u.Scheme == "" || u.Opaque != "" {
+		return nil, newPathError(ErrInvalidValue, "config: URL %q at %q is not absolute", s, path)
+	}
+	return u, nil
+}
+
+func (c *ConfigImpl) MustURL(path string, defaults ...*url.URL) *url.URL {
+	u, err := c.URL(path)
+	if err == nil && !c.useDefault(path, len(defaults)) {
+		return u
+	}
+	for _, def := range defaults {
+		return def
+	}
+	return nil
+}
+
+// Regexp compiles the regular expression at the dotted path, so a bad
+// pattern is caught when the config is read rather than on first use.
+func (c *ConfigImpl) Regexp(path string) (*regexp.Regexp, error) {
+	s, err := c.String(path)
+	if err != nil {
+		return nil, err
+	}
+	re, err := regexp.Compile(s)
+	if err != nil {
+		return nil, newPathError(ErrInvalidValue, "config: Invalid regexp at %q: %v", path, err)
+	}
+	return re, nil
+}
+
+func (c *ConfigImpl) MustRegexp(path string, defaults ...*regexp.Regexp) *regexp.Regexp {
+	re, err := c.Regexp(path)
+	if err == nil && !c.useDefault(path, len(defaults)) {
+		return re
+	}
+	for _, def := range defaults {
+		return def
+	}
+	return nil
+}
+
+// Base64 decodes the standard base64 string at the dotted path. With
+// WithBase64Fallback, unpadded and URL-safe encodings are tried in turn.
+func (c *ConfigImpl) Base64(path string) ([]byte, error) {
+	s, err := c.String(path)
+	if err != nil {
+		return nil, err
+	}
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err == nil {
+		return b, nil
+	}
+	if c.opts.base64Fallback {
+		for _, enc := range []*base64.Encoding{base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+			if b, ferr := enc.DecodeString(s); ferr == nil {
+				return b, nil
+			}
+		}
+	}
+	return nil, newPathError(ErrInvalidValue, "config: Invalid base64 at %q: %v", path, err)
+}
+
+func (c *ConfigImpl) MustBase64(path string, defaults ...[]byte) []byte {
+	b, err := c.Base64(path)
+	if err == nil && !c.useDefault(path, len(defaults)) {
+		return b
+	}
+	for _, def := range defaults {
+		return def
+	}
+	return nil
+}
+
+// StringPtr returns a pointer to the string at the path, or nil when the path
+// is absent or not a string. The pointer getters tell an explicit zero value
+// from a missing one and suit optional struct fields of pointer type.
+func (c *ConfigImpl) StringPtr(path string) *string {
+	s, err := c.String(path)
+	if err != nil {
+		return nil
+	}
+	return &s
+}
+
+// IntPtr is the int counterpart of StringPtr.
+func (c *ConfigImpl) IntPtr(path string) *int {
+	i, err := c.Int(path)
+	if err != nil {
+		return nil
+	}
+	return &i
+}
+
+// BoolPtr is the bool counterpart of StringPtr.
+func (c *ConfigImpl) BoolPtr(path string) *bool {
+	b, err := c.Bool(path)
+	if err != nil {
+		return nil
+	}
+	return &b
+}
+
+// FloatPtr is the float64 counterpart of StringPtr.
+func (c *ConfigImpl) FloatPtr(path string) *float64 {
+	f, err := c.Float(path)
+	if err != nil {
+		return nil
+	}
+	return &f
+}
+
+// BoolCoerce is a lenient Bool for values that arrive as text, such as env
+// overrides and INI files: besides bools it accepts the numbers 1 and 0 and
+// the strings "1", "t", "true", "yes", "on" and "0", "f", "false", "no",
+// "off" in any case. Anything else is ErrWrongType. Bool stays strict.
+func (c *ConfigImpl) BoolCoerce(path string) (bool, error) {
+	x, err := c.Get(path)
+	if err != nil {
+		return false, err
+	}
+	if b, ok := coerceBool(x); ok {
+		return b, nil
+	}
+	return false, wrongType(x, path)
+}
+
+// TypeOf returns the JSON type of the value at the path: "string", "bool",
+// "number", "object", "array" or "null". Values of other Go types, which can
+// only be placed with Set, are named by their Go type.
+func (c *ConfigImpl) TypeOf(path string) (string, error) {
+	x, err := c.Get(path)
+	if err != nil {
+		return "", err
+	}
+	return jsonTypeName(x), nil
+}
+
+// StringOr returns the string at the path, or def when the path is missing
+// or not a string. Unlike MustString it takes exactly one default.
+func (c *ConfigImpl) StringOr(path string, def string) string {
+	return c.MustString(path, def)
+}
+
+// StringOrFunc is StringOr with a default computed by fn, which is only
+// called when the default is needed, e.g. to generate a random secret.
+func (c *ConfigImpl) StringOrFunc(path string, fn func() string) string {
+	s, err := c.String(path)
+	if err == nil && !c.useDefault(path, 1) {
+		return s
+	}
+	return fn()
+}
+
+// IntOr is the int counterpart of StringOr.
+func (c *ConfigImpl) IntOr(path string, def int) int {
+	return c.MustInt(path, def)
+}
+
+// BoolOr is the bool counterpart of StringOr.
+func (c *ConfigImpl) BoolOr(path string, def bool) bool {
+	return c.MustBool(path, def)
+}
+
+// FloatOr is the float64 counterpart of StringOr.
+func (c *ConfigImpl) FloatOr(path string, def float64) float64 {
+	return c.MustFloat(path, def)
+}
+
+// IntStrict is Int for values that must be written as integer literals:
+// "replicas": 3 is accepted but 3.0 or 3e0 is ErrWrongType. Only configs
+// parsed with UseNumber keep the original token, so without it every JSON
+// number is rejected; integers placed with Set are accepted either way.
+func (c *ConfigImpl) IntStrict(path string) (int, error) {
+	x, err := c.Get(path)
+	if err != nil {
+		return -1, err
+	}
+	switch v := x.(type) {
+	case json.Number:
+		if strings.ContainsAny(string(v), ".eE") {
+			return -1, newPathError(ErrWrongType, "config: Expected integer literal at %q, got %s", path, v)
+		}
+	case float64, float32:
+		return -1, newPathError(ErrWrongType, "config: Expected integer literal at %q, got %v", path, v)
+	}
+	if i, ok := toInt64(x); ok && int64(int(i)) == i {
+		return int(i), nil
+	}
+	return -1, wrongType(x, path)
+}
+
+// IntTrunc is Int for values that may have a fraction, which is dropped:
+// 5.9 reads as 5 and -5.5 as -5.
+func (c *ConfigImpl) IntTrunc(path string) (int, error) {
+	return c.intFrom(path, math.Trunc)
+}
+
+// IntRound is Int for values that may have a fraction, rounded to the
+// nearest integer with halves away from zero: 5.5 reads as 6 and -5.5 as -6.
+func (c *ConfigImpl) IntRound(path string) (int, error) {
+	return c.intFrom(path, math.Round)
+}
+
+func (c *ConfigImpl) intFrom(path string, conv func(float64) float64) (int, error) {
+	x, err := c.Get(path)
+	if err != nil {
+		return -1, err
+	}
+	if i, ok := toInt64(x); ok && int64(int(i)) == i {
+		return int(i), nil
+	}
+	f, ok := toFloat64(x)
+	if !ok || math.IsNaN(f) {
+		return -1, wrongType(x, path)
+	}
+	f = conv(f)
+	if f < math.MinInt64 || f >= math.MaxInt64 || int64(int(f)) != int64(f) {
+		return -1, newPathError(ErrWrongType, "config: Value %v at %q is out of range for int", x, path)
+	}
+	return int(f), nil
+}
+
+// IntMap returns the object at the path with every value read as by Int, for
+// homogeneous sections such as {"read": 100, "write": 50}. A value of another
+// type is ErrWrongType naming its key.
+func (c *ConfigImpl) IntMap(path string) (map[string]int, error) {
+	m, err := c.Map(path)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]int, len(m))
+	for k, v := range m {
+		i, ok := toInt64(v)
+		if !ok || int64(int(i)) != i {
+			return nil, wrongType(v, c.childPath(path, k))
+		}
+		out[k] = int(i)
+	}
+	return out, nil
+}
+
+func (c *ConfigImpl) MustIntMap(path string, defaults ...map[string]int) map[string]int {
+	m, err := c.IntMap(path)
+	if err == nil && !c.useDefault(path, len(defaults)) {
+		return m
+	}
+	for _, def := range defaults {
+		return def
+	}
+	return map[string]int{}
+}
+
+// StringMap is the string counterpart of IntMap. WithStringCoercion applies
+// to the values.
+func (c *ConfigImpl) StringMap(path string) (map[string]string, error) {
+	m, err := c.Map(path)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		s, ok := v.(string)
+		if !ok && c.opts.stringCoercion {
+			s, ok = coerceString(v)
+		}
+		if !ok {
+			return nil, wrongType(v, c.childPath(path, k))
+		}
+		out[k] = s
+	}
+	return out, nil
+}
+
+func (c *ConfigImpl) MustStringMap(path string, defaults ...map[string]string) map[string]string {
+	m, err := c.StringMap(path)
+	if err == nil && !c.useDefault(path, len(defaults)) {
+		return m
+	}
+	for _, def := range defaults {
+		return def
+	}
+	return map[string]string{}
+}
+
+// FloatMap is the float64 counterpart of IntMap.
+func (c *ConfigImpl) FloatMap(path string) (map[string]float64, error) {
+	m, err := c.Map(path)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]float64, len(m))
+	for k, v := range m {
+		f, ok := toFloat64(v)
+		if !ok {
+			return nil, wrongType(v, c.childPath(path, k))
+		}
+		out[k] = f
+	}
+	return out, nil
+}
+
+func (c *ConfigImpl) MustFloatMap(path string, defaults ...map[string]float64) map[string]float64 {
+	m, err := c.FloatMap(path)
+	if err == nil && !c.useDefault(path, len(defaults)) {
+		return m
+	}
+	for _, def := range defaults {
+		return def
+	}
+	return map[string]float64{}
+}
+
+// BoolMap is the bool counterpart of IntMap.
+func (c *ConfigImpl) BoolMap(path string) (map[string]bool, error) {
+	m, err := c.Map(path)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]bool, len(m))
+	for k, v := range m {
+		b, ok := v.(bool)
+		if !ok {
+			return nil, wrongType(v, c.childPath(path, k))
+		}
+		out[k] = b
+	}
+	return out, nil
+}
+
+func (c *ConfigImpl) MustBoolMap(path string, defaults ...map[string]bool) map[string]bool {
+	m, err := c.BoolMap(path)
+	if err == nil && !c.useDefault(path, len(defaults)) {
+		return m
+	}
+	for _, def := range defaults {
+		return def
+	}
+	return map[string]bool{}
+}
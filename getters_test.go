@@ -0,0 +1,318 @@
+package config_test
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigURL(t *testing.T) {
+	cfg, err := config.ParseJSON(`{
+		"api": "https://api.example.com:8443/v1?x=1",
+		"bare": "localhost:8080",
+		"relative": "/v1/users",
+		"broken": "http://[::1",
+		"port": 80
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := cfg.URL("api")
+	assert.NoError(t, err)
+	assert.Equal(t, "https", u.Scheme)
+	assert.Equal(t, "api.example.com:8443", u.Host)
+	assert.Equal(t, "/v1", u.Path)
+
+	for _, path := range []string{"bare", "relative", "broken"} {
+		_, err = cfg.URL(path)
+		assert.True(t, errors.Is(err, config.ErrInvalidValue), path)
+		assert.Contains(t, err.Error(), path)
+	}
+	_, err = cfg.URL("port")
+	assert.True(t, errors.Is(err, config.ErrWrongType))
+
+	def, _ := url.Parse("http://localhost")
+	assert.Equal(t, def, cfg.MustURL("bare", def))
+	assert.Nil(t, cfg.MustURL("missing"))
+}
+
+func Test_ConfigRegexp(t *testing.T) {
+	cfg, err := config.ParseJSON(`{"route": "^/users/(\\d+)$", "bad": "([a-z"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	re, err := cfg.Regexp("route")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/users/42", "42"}, re.FindStringSubmatch("/users/42"))
+
+	_, err = cfg.Regexp("bad")
+	assert.True(t, errors.Is(err, config.ErrInvalidValue))
+	assert.Contains(t, err.Error(), `"bad"`)
+	assert.Nil(t, cfg.MustRegexp("bad"))
+	assert.Equal(t, "x", cfg.MustRegexp("bad", regexp.MustCompile("x")).String())
+}
+
+func Test_ConfigBase64(t *testing.T) {
+	doc := `{"key": "aGVsbG8=", "raw": "aGVsbG8", "urlsafe": "-_8=", "bad": "%%%"}`
+	cfg, err := config.ParseJSON(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := cfg.Base64("key")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("hello"), b)
+
+	_, err = cfg.Base64("raw")
+	assert.True(t, errors.Is(err, config.ErrInvalidValue))
+	assert.Contains(t, err.Error(), `"raw"`)
+	assert.Nil(t, cfg.MustBase64("bad"))
+
+	cfg, err = config.ParseJSON(doc, config.WithBase64Fallback())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []byte("hello"), cfg.MustBase64("raw"))
+	assert.Equal(t, []byte{0xfb, 0xff}, cfg.MustBase64("urlsafe"))
+	_, err = cfg.Base64("bad")
+	assert.Error(t, err)
+}
+
+func Test_ConfigPointerGetters(t *testing.T) {
+	cfg, err := config.ParseJSON(`{"name": "", "port": 0, "debug": false, "ratio": 0.5, "mixed": "x"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if assert.NotNil(t, cfg.StringPtr("name")) {
+		assert.Equal(t, "", *cfg.StringPtr("name"))
+	}
+	if assert.NotNil(t, cfg.IntPtr("port")) {
+		assert.Equal(t, 0, *cfg.IntPtr("port"))
+	}
+	if assert.NotNil(t, cfg.BoolPtr("debug")) {
+		assert.False(t, *cfg.BoolPtr("debug"))
+	}
+	if assert.NotNil(t, cfg.FloatPtr("ratio")) {
+		assert.Equal(t, 0.5, *cfg.FloatPtr("ratio"))
+	}
+
+	assert.Nil(t, cfg.StringPtr("missing"))
+	assert.Nil(t, cfg.IntPtr("missing"))
+	assert.Nil(t, cfg.BoolPtr("missing"))
+	assert.Nil(t, cfg.FloatPtr("missing"))
+
+	assert.Nil(t, cfg.StringPtr("port"))
+	assert.Nil(t, cfg.IntPtr("mixed"))
+	assert.Nil(t, cfg.IntPtr("ratio"))
+	assert.Nil(t, cfg.BoolPtr("mixed"))
+	assert.Nil(t, cfg.FloatPtr("mixed"))
+}
+
+func Test_ConfigBoolCoerce(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{`true`, true}, {`false`, false},
+		{`1`, true}, {`0`, false},
+		{`"1"`, true}, {`"0"`, false},
+		{`"t"`, true}, {`"F"`, false},
+		{`"true"`, true}, {`"FALSE"`, false},
+		{`"True"`, true}, {`"False"`, false},
+		{`"yes"`, true}, {`"No"`, false},
+		{`"ON"`, true}, {`"off"`, false},
+	}
+	for _, tt := range tests {
+		cfg, err := config.ParseJSON(`{"flag": ` + tt.value + `}`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := cfg.BoolCoerce("flag")
+		assert.NoError(t, err, tt.value)
+		assert.Equal(t, tt.want, b, tt.value)
+	}
+
+	for _, value := range []string{`2`, `0.5`, `"maybe"`, `""`, `null`, `[]`} {
+		cfg, err := config.ParseJSON(`{"flag": ` + value + `}`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = cfg.BoolCoerce("flag")
+		assert.True(t, errors.Is(err, config.ErrWrongType), value)
+	}
+
+	cfg, err := config.ParseJSON(`{"flag": "true"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = cfg.Bool("flag")
+	assert.True(t, errors.Is(err, config.ErrWrongType))
+}
+
+func Test_ConfigTypedMaps(t *testing.T) {
+	cfg, err := config.ParseJSON(`{
+		"limits": {"read": 100, "write": 50},
+		"labels": {"team": "core", "tier": "1"},
+		"weights": {"a": 0.5, "b": 2},
+		"features": {"search": true, "beta": false},
+		"mixed": {"ok": 1, "bad": "x"},
+		"dotted": {"log.level": "debug"}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	limits, err := cfg.IntMap("limits")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"read": 100, "write": 50}, limits)
+	assert.Equal(t, map[string]string{"team": "core", "tier": "1"}, cfg.MustStringMap("labels"))
+	assert.Equal(t, map[string]float64{"a": 0.5, "b": 2}, cfg.MustFloatMap("weights"))
+	assert.Equal(t, map[string]bool{"search": true, "beta": false}, cfg.MustBoolMap("features"))
+
+	_, err = cfg.IntMap("mixed")
+	assert.True(t, errors.Is(err, config.ErrWrongType))
+	assert.EqualError(t, err, `config: Unknown type at "mixed.bad"`)
+	_, err = cfg.IntMap("weights")
+	assert.EqualError(t, err, `config: Unknown type at "weights.a"`)
+	_, err = cfg.IntMap("dotted")
+	var pathErr *config.PathError
+	assert.True(t, errors.As(err, &pathErr))
+	assert.Equal(t, `dotted.log\.level`, pathErr.FullPath)
+	assert.True(t, cfg.Has(pathErr.FullPath))
+	_, err = cfg.StringMap("limits")
+	assert.Error(t, err)
+	_, err = cfg.BoolMap("labels")
+	assert.Error(t, err)
+
+	assert.Equal(t, map[string]int{}, cfg.MustIntMap("mixed"))
+	assert.Equal(t, map[string]string{}, cfg.MustStringMap("missing"))
+	assert.Equal(t, map[string]float64{}, cfg.MustFloatMap("missing"))
+	assert.Equal(t, map[string]bool{}, cfg.MustBoolMap("missing"))
+	assert.Equal(t, map[string]int{"x": 1}, cfg.MustIntMap("missing", map[string]int{"x": 1}))
+}
+
+func Test_ConfigIntStrict(t *testing.T) {
+	cfg, err := config.ParseJSON(`{"replicas": 3, "float": 3.0, "exp": 3e0, "name": "x"}`, config.UseNumber())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	i, err := cfg.IntStrict("replicas")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, i)
+	assert.Equal(t, 3, cfg.MustInt("float"))
+	_, err = cfg.IntStrict("float")
+	assert.True(t, errors.Is(err, config.ErrWrongType))
+	assert.EqualError(t, err, `config: Expected integer literal at "float", got 3.0`)
+	_, err = cfg.IntStrict("exp")
+	assert.True(t, errors.Is(err, config.ErrWrongType))
+	_, err = cfg.IntStrict("name")
+	assert.True(t, errors.Is(err, config.ErrWrongType))
+	_, err = cfg.IntStrict("missing")
+	assert.True(t, errors.Is(err, config.ErrNotFound))
+
+	plain, err := config.ParseJSON(`{"replicas": 3}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = plain.IntStrict("replicas")
+	assert.True(t, errors.Is(err, config.ErrWrongType))
+}
+
+func Test_ConfigIntTruncRound(t *testing.T) {
+	cfg, err := config.ParseJSON(`{"a": 5.1, "b": 5.9, "c": -5.5, "d": 26, "name": "x", "huge": 1e30}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for path, want := range map[string][2]int{
+		"a": {5, 5},
+		"b": {5, 6},
+		"c": {-5, -6},
+		"d": {26, 26},
+	} {
+		i, err := cfg.IntTrunc(path)
+		assert.NoError(t, err)
+		assert.Equal(t, want[0], i, path)
+		i, err = cfg.IntRound(path)
+		assert.NoError(t, err)
+		assert.Equal(t, want[1], i, path)
+	}
+
+	_, err = cfg.Int("a")
+	assert.True(t, errors.Is(err, config.ErrWrongType))
+	_, err = cfg.IntTrunc("name")
+	assert.True(t, errors.Is(err, config.ErrWrongType))
+	_, err = cfg.IntRound("huge")
+	assert.True(t, errors.Is(err, config.ErrWrongType))
+	_, err = cfg.IntTrunc("missing")
+	assert.True(t, errors.Is(err, config.ErrNotFound))
+}
+
+func Test_ConfigOrGetters(t *testing.T) {
+	cfg, err := config.ParseJSON(`{"name": "api", "port": 8080, "debug": false, "ratio": 0.5, "secret": "s3cret"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "api", cfg.StringOr("name", "web"))
+	assert.Equal(t, "web", cfg.StringOr("missing", "web"))
+	assert.Equal(t, "web", cfg.StringOr("port", "web"))
+	assert.Equal(t, "", cfg.StringOr("missing", ""))
+	assert.Equal(t, 8080, cfg.IntOr("port", 80))
+	assert.Equal(t, 80, cfg.IntOr("missing", 80))
+	assert.False(t, cfg.BoolOr("debug", true))
+	assert.True(t, cfg.BoolOr("missing", true))
+	assert.Equal(t, 0.5, cfg.FloatOr("ratio", 1))
+	assert.Equal(t, 1.0, cfg.FloatOr("missing", 1))
+
+	calls := 0
+	generate := func() string {
+		calls++
+		return "generated"
+	}
+	assert.Equal(t, "s3cret", cfg.StringOrFunc("secret", generate))
+	assert.Equal(t, 0, calls)
+	assert.Equal(t, "generated", cfg.StringOrFunc("token", generate))
+	assert.Equal(t, 1, calls)
+}
+
+func Test_ConfigTypeOf(t *testing.T) {
+	cfg, err := config.ParseJSONFile("resources/config/default.conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for path, want := range map[string]string{
+		"name":                "string",
+		"debug":               "bool",
+		"age":                 "number",
+		"height":              "number",
+		"clothes":             "object",
+		"hobbies":             "array",
+		"nested.1.2.3.0":      "object",
+		"nested.1.2.3.0.a":    "number",
+		"clothes.pants.waist": "number",
+	} {
+		typ, err := cfg.TypeOf(path)
+		assert.NoError(t, err, path)
+		assert.Equal(t, want, typ, path)
+	}
+	_, err = cfg.TypeOf("missing")
+	assert.True(t, errors.Is(err, config.ErrNotFound))
+
+	null, err := config.ParseJSON(`{"proxy": null}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	typ, err := null.TypeOf("proxy")
+	assert.NoError(t, err)
+	assert.Equal(t, "null", typ)
+}
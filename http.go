@@ -0,0 +1,127 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultHTTPTimeout bounds ParseJSONURL when no timeout option is given.
+const defaultHTTPTimeout = 30 * time.Second
+
+// WithHTTPClient sets the client used by ParseJSONURL. The default is a
+// client with a 30 second timeout.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) {
+		o.httpClient = client
+	}
+}
+
+// WithHTTPTimeout bounds the whole request made by ParseJSONURL, including
+// reading the body. It overrides the timeout of a client set with
+// WithHTTPClient without modifying that client.
+func WithHTTPTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.httpTimeout = d
+	}
+}
+
+// ParseJSONURL fetches the JSON document at the HTTP or HTTPS URL and parses
+// it. Responses other than 2xx are errors that include the status, as are
+// content types other than JSON and plain text; a missing content type is
+// accepted.
+func ParseJSONURL(url string, opts ...Option) (Config, error) {
+	return ParseJSONURLContext(context.Background(), url, opts...)
+}
+
+// ParseJSONURLContext is ParseJSONURL with a context: cancelling it or
+// reaching its deadline aborts the fetch, and the error then wraps
+// ctx.Err(). The HTTP timeout options still apply.
+func ParseJSONURLContext(ctx context.Context, url string, opts ...Option) (Config, error) {
+	o := newOptions(opts)
+	data, err := fetchURL(ctx, url, o)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := parseJSON(data, o)
+	if err != nil {
+		return nil, fmt.Errorf("config: parsing %q: %w", url, err)
+	}
+	cfg.origin = "url:" + url
+	return cfg, nil
+}
+
+func fetchURL(ctx context.Context, url string, o options) ([]byte, error) {
+	client := o.httpClient
+	if client == nil {
+		client = &http.Client{Timeout: defaultHTTPTimeout}
+	}
+	if o.httpTimeout > 0 {
+		c := *client
+		c.Timeout = o.httpTimeout
+		client = &c
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("config: fetching %q: %w", url, err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fetchFailure(ctx, url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("config: fetching %q: unexpected status %s", url, resp.Status)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "" && !jsonContentType(ct) {
+		return nil, fmt.Errorf("config: fetching %q: unexpected content type %q", url, ct)
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fetchFailure(ctx, url, err)
+	}
+	return data, nil
+}
+
+// fetchFailure wraps ctx.Err() rather than err once the context is done, so
+// callers can match context.Canceled and context.DeadlineExceeded directly.
+func fetchFailure(ctx context.Context, url string, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return fmt.Errorf("config: fetching %q: %w", url, ctxErr)
+	}
+	return fmt.Errorf("config: fetching %q: %w", url, err)
+}
+
+// jsonContentType accepts application/json, any +json type and text/plain.
+func jsonContentType(ct string) bool {
+	mt, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return false
+	}
+	return mt == "application/json" || mt == "text/plain" || strings.HasSuffix(mt, "+json")
+}
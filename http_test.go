@@ -0,0 +1,86 @@
+package config_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigParseJSONURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/app.json":
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.Write([]byte(`{"env": "production", "port": 8080}`))
+		case "/plain":
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte(`{"env": "plain"}`))
+		case "/html":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<html></html>`))
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+			w.Write([]byte(`{}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	cfg, err := config.ParseJSONURL(srv.URL + "/app.json")
+	assert.NoError(t, err)
+	assert.Equal(t, "production", cfg.MustString("env"))
+	assert.Equal(t, 8080, cfg.MustInt("port"))
+
+	cfg, err = config.ParseJSONURL(srv.URL+"/plain", config.WithHTTPClient(srv.Client()))
+	assert.NoError(t, err)
+	assert.Equal(t, "plain", cfg.MustString("env"))
+
+	_, err = config.ParseJSONURL(srv.URL + "/missing")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "404 Not Found")
+
+	_, err = config.ParseJSONURL(srv.URL + "/html")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `"text/html"`)
+
+	_, err = config.ParseJSONURL(srv.URL+"/slow", config.WithHTTPTimeout(20*time.Millisecond))
+	assert.Error(t, err)
+}
+
+func Test_ConfigParseJSONURLContext(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/hang" {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		}
+		w.Write([]byte(`{"env": "production"}`))
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	cfg, err := config.ParseJSONURLContext(context.Background(), srv.URL+"/app.json")
+	assert.NoError(t, err)
+	assert.Equal(t, "production", cfg.MustString("env"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = config.ParseJSONURLContext(ctx, srv.URL+"/hang")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.True(t, time.Since(start) < 5*time.Second)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	_, err = config.ParseJSONURLContext(ctx, srv.URL+"/app.json")
+	assert.True(t, errors.Is(err, context.Canceled))
+}
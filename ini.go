@@ -0,0 +1,118 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// ParseINIFile reads an INI file. Keys before the first [section] are
+// top-level; each [section] becomes a top-level map holding the keys below
+// it, so "host" under [database] is read as "database.host". Dotted section
+// names nest ([database.replica]). Lines starting with ";" or "#" are
+// comments, as is the rest of an unquoted value after " ;" or " #". Values
+// are quoted and coerced as in ParsePropertiesFile. A key repeated within a
+// section keeps its last value; a repeated section adds to the first one.
+func ParseINIFile(path string, opts ...Option) (Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := parseINI(data, newOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	cfg.origin = fileSource(path)
+	return cfg, nil
+}
+
+func parseINI(data []byte, o options) (*ConfigImpl, error) {
+	flat := map[string]interface{}{}
+	var sections []string
+	section := ""
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+		if line[0] == '[' {
+			end := strings.IndexByte(line, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("config: line %d: unterminated section %q", n, line)
+			}
+			section = strings.TrimSpace(line[1:end])
+			if section == "" {
+				return nil, fmt.Errorf("config: line %d: empty section name", n)
+			}
+			sections = append(sections, section)
+			continue
+		}
+		eq := strings.Index(line, "=")
+		if eq < 0 {
+			return nil, fmt.Errorf("config: line %d: missing \"=\" in %q", n, line)
+		}
+		key := strings.TrimSpace(line[:eq])
+		if key == "" {
+			return nil, fmt.Errorf("config: line %d: empty key", n)
+		}
+		value, err := parseRawValue(strings.TrimSpace(line[eq+1:]), ";#")
+		if err != nil {
+			return nil, fmt.Errorf("config: line %d: %v", n, err)
+		}
+		if section != "" {
+			key = section + defaultSeparator + key
+		}
+		flat[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	root, err := unflatten(flat, defaultSeparator)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range sections {
+		var node interface{} = root
+		for _, part := range splitPath(s, defaultSeparator) {
+			m, ok := node.(map[string]interface{})
+			if !ok {
+				break
+			}
+			next, exists := m[part]
+			if !exists {
+				next = map[string]interface{}{}
+				m[part] = next
+			}
+			switch next.(type) {
+			case map[string]interface{}, []interface{}:
+			default:
+				return nil, fmt.Errorf("config: Section %q conflicts with a key", s)
+			}
+			node = next
+		}
+	}
+	return newParsed(root, o)
+}
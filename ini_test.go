@@ -0,0 +1,23 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigParseINIFile(t *testing.T) {
+	cfg, err := config.ParseINIFile("resources/config/app.ini")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "billing", cfg.MustString("name"))
+	assert.Equal(t, false, cfg.MustBool("debug", true))
+	assert.Equal(t, "db.internal", cfg.MustString("database.host"))
+	assert.Equal(t, 6432, cfg.MustInt("database.port"))
+	assert.Equal(t, 2.5, cfg.MustFloat("database.timeout"))
+	assert.Equal(t, true, cfg.MustBool("cache.enabled"))
+	assert.Equal(t, "redis://cache:6379/0", cfg.MustString("cache.url"))
+}
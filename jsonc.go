@@ -0,0 +1,118 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"errors"
+	"io/ioutil"
+)
+
+// ParseJSONC parses JSON with comments, as written by ToJSONC and by hand:
+// "//" line comments, "/* */" block comments and trailing commas before a
+// closing bracket or brace are accepted. Comment markers inside string
+// literals, such as the "//" of a URL, are left alone.
+func ParseJSONC(data string, opts ...Option) (Config, error) {
+	b, err := stripJSONC([]byte(data))
+	if err != nil {
+		return nil, err
+	}
+	return parseJSON(b, newOptions(opts))
+}
+
+// ParseJSONCFile reads and parses a JSONC file. See ParseJSONC.
+func ParseJSONCFile(path string, opts ...Option) (Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	b, err := stripJSONC(data)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := parseJSON(b, newOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	cfg.origin = fileSource(path)
+	return cfg, nil
+}
+
+// stripJSONC blanks out comments and trailing commas with spaces, keeping
+// newlines, so offsets in JSON syntax errors still match the input once
+// decodeText has removed any byte order mark.
+func stripJSONC(data []byte) ([]byte, error) {
+	data, err := decodeText(data)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(data))
+	copy(out, data)
+	inString := false
+	for i := 0; i < len(out); i++ {
+		ch := out[i]
+		switch {
+		case inString:
+			if ch == '\\' {
+				i++
+			} else if ch == '"' {
+				inString = false
+			}
+		case ch == '"':
+			inString = true
+		case ch == '/' && i+1 < len(out) && out[i+1] == '/':
+			for ; i < len(out) && out[i] != '\n'; i++ {
+				out[i] = ' '
+			}
+		case ch == '/' && i+1 < len(out) && out[i+1] == '*':
+			end := -1
+			for j := i + 2; j+1 < len(out); j++ {
+				if out[j] == '*' && out[j+1] == '/' {
+					end = j + 2
+					break
+				}
+			}
+			if end < 0 {
+				return nil, errors.New("config: unterminated block comment")
+			}
+			for ; i < end; i++ {
+				if out[i] != '\n' {
+					out[i] = ' '
+				}
+			}
+			i--
+		case ch == ']' || ch == '}':
+			for j := i - 1; j >= 0; j-- {
+				if out[j] == ',' {
+					out[j] = ' '
+					break
+				}
+				if !isJSONSpace(out[j]) {
+					break
+				}
+			}
+		}
+	}
+	return out, nil
+}
+
+func isJSONSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
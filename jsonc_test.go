@@ -0,0 +1,32 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigParseJSONC(t *testing.T) {
+	cfg, err := config.ParseJSONCFile("resources/config/commented.jsonc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "0.0.0.0:8080", cfg.MustString("listen"))
+	assert.Equal(t, "http://backend.internal/api", cfg.MustString("upstream"))
+	assert.Equal(t, "not /* a comment */ either", cfg.MustString("note"))
+	assert.Equal(t, []interface{}{"a", "b"}, cfg.MustList("hosts"))
+
+	cfg, err = config.ParseJSONC(`{"path": "C:\\dir\\", /* after an escaped backslash */ "n": 1,}`)
+	assert.NoError(t, err)
+	assert.Equal(t, `C:\dir\`, cfg.MustString("path"))
+	assert.Equal(t, 1, cfg.MustInt("n"))
+
+	_, err = config.ParseJSONC(`{"a": 1 /* never closed }`)
+	assert.Error(t, err)
+	_, err = config.ParseJSONC(`{"a": 1,, }`)
+	assert.Error(t, err)
+	cfg, err = config.ParseJSONC(`{"l": [1, 2 /* ] */,]}`)
+	assert.NoError(t, err)
+	assert.Len(t, cfg.MustList("l"), 2)
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+)
+
+// checkDuplicateKeys streams through the JSON document and reports the
+// first object key that appears twice in the same object. Syntax errors are
+// left for the decoder that parses the document.
+func checkDuplicateKeys(data []byte, sep string) error {
+	type frame struct {
+		path    string
+		keys    map[string]bool // nil for a list
+		index   int
+		key     string // the key whose value comes next, when haveKey
+		haveKey bool
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	var stack []*frame
+	// childPath returns the path of the value about to be read.
+	childPath := func() string {
+		if len(stack) == 0 {
+			return ""
+		}
+		top := stack[len(stack)-1]
+		if top.keys != nil {
+			return joinPath(top.path, top.key, sep)
+		}
+		return joinPath(top.path, strconv.Itoa(top.index), sep)
+	}
+	// done records that a value of the innermost container was read.
+	done := func() {
+		if len(stack) == 0 {
+			return
+		}
+		top := stack[len(stack)-1]
+		if top.keys == nil {
+			top.index++
+		} else {
+			top.haveKey = false
+		}
+	}
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil
+		}
+		if len(stack) > 0 {
+			top := stack[len(stack)-1]
+			if s, ok := tok.(string); ok && top.keys != nil && !top.haveKey {
+				if top.keys[s] {
+					path := joinPath(top.path, s, sep)
+					return fetchError(ErrInvalidValue, path, path, "config: Duplicate key %q at %q", s, path)
+				}
+				top.keys[s] = true
+				top.key, top.haveKey = s, true
+				continue
+			}
+		}
+		switch tok {
+		case json.Delim('{'):
+			stack = append(stack, &frame{path: childPath(), keys: map[string]bool{}})
+		case json.Delim('['):
+			stack = append(stack, &frame{path: childPath()})
+		case json.Delim('}'), json.Delim(']'):
+			stack = stack[:len(stack)-1]
+			done()
+		default:
+			done()
+		}
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// KeyStyle selects how NormalizeKeys rewrites map keys.
+type KeyStyle int
+
+const (
+	// KeepKeys leaves keys as written.
+	KeepKeys KeyStyle = iota
+	// SnakeCase writes keys as "http_server_port".
+	SnakeCase
+	// CamelCase writes keys as "httpServerPort".
+	CamelCase
+	// KebabCase writes keys as "http-server-port".
+	KebabCase
+)
+
+// NormalizeKeys rewrites every map key at parse time into style, so that
+// code can use one spelling of paths whatever the source used: with
+// SnakeCase, "maxConns", "max-conns" and "MaxConns" all become "max_conns".
+// Words are split at underscores, hyphens, spaces and changes of case, with
+// runs of capitals kept together ("HTTPServer" is "http" and "server"). Two
+// keys of one map that normalize to the same key are an error.
+func NormalizeKeys(style KeyStyle) Option {
+	return func(o *options) {
+		o.keyStyle = style
+	}
+}
+
+// normalizeKeys returns a copy of node with its map keys rewritten in the
+// style selected by o.
+func normalizeKeys(node interface{}, path string, o options) (interface{}, error) {
+	sep := o.separator()
+	switch x := node.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		out := make(map[string]interface{}, len(x))
+		from := make(map[string]string, len(x))
+		for _, k := range keys {
+			nk := styleKey(k, o.keyStyle)
+			if prev, ok := from[nk]; ok {
+				at := joinPath(path, nk, sep)
+				return nil, newPathError(ErrInvalidValue, "config: Keys %q and %q both normalize to %q", prev, k, at)
+			}
+			from[nk] = k
+			v, err := normalizeKeys(x[k], joinPath(path, nk, sep), o)
+			if err != nil {
+				return nil, err
+			}
+			out[nk] = v
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(x))
+		for i, e := range x {
+			v, err := normalizeKeys(e, joinPath(path, strconv.Itoa(i), sep), o)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = v
+		}
+		return out, nil
+	}
+	return node, nil
+}
+
+// styleKey rewrites key in style.
+func styleKey(key string, style KeyStyle) string {
+	words := keyWords(key)
+	if len(words) == 0 {
+		return key
+	}
+	switch style {
+	case SnakeCase:
+		return strings.Join(words, "_")
+	case KebabCase:
+		return strings.Join(words, "-")
+	case CamelCase:
+		for i := 1; i < len(words); i++ {
+			r := []rune(words[i])
+			r[0] = unicode.ToUpper(r[0])
+			words[i] = string(r)
+		}
+		return strings.Join(words, "")
+	}
+	return key
+}
+
+// keyWords splits key into lower-case words.
+func keyWords(key string) []string {
+	var words []string
+	var cur []rune
+	flush := func() {
+		if len(cur) > 0 {
+			words = append(words, strings.ToLower(string(cur)))
+			cur = cur[:0]
+		}
+	}
+	runes := []rune(key)
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-' || unicode.IsSpace(r):
+			flush()
+			continue
+		case unicode.IsUpper(r) && len(cur) > 0:
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if !unicode.IsUpper(prev) || nextLower {
+				flush()
+			}
+		}
+		cur = append(cur, r)
+	}
+	flush()
+	return words
+}
@@ -0,0 +1,43 @@
+package config_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigNormalizeKeys(t *testing.T) {
+	cfg, err := config.ParseJSONFile("resources/config/mixed-case.json", config.NormalizeKeys(config.SnakeCase))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string]interface{}{
+		"service_name": "billing",
+		"http_server": map[string]interface{}{
+			"listen_port":  8080.0,
+			"read_timeout": "5s",
+			"tls_enabled":  true,
+		},
+		"db_hosts": []interface{}{
+			map[string]interface{}{"host_name": "db1", "port": 5432.0},
+		},
+		"max_conns": 10.0,
+	}, cfg.Raw())
+
+	cfg, err = config.ParseJSONFile("resources/config/mixed-case.json", config.NormalizeKeys(config.CamelCase))
+	assert.NoError(t, err)
+	assert.Equal(t, 8080, cfg.MustInt("httpServer.listenPort"))
+	assert.Equal(t, "db1", cfg.MustString("dbHosts.0.hostName"))
+
+	cfg, err = config.ParseJSONFile("resources/config/mixed-case.json", config.NormalizeKeys(config.KebabCase), config.Lazy())
+	assert.NoError(t, err)
+	assert.Equal(t, "5s", cfg.MustString("http-server.read-timeout"))
+
+	_, err = config.ParseJSON(`{"db": {"maxConns": 1, "max_conns": 2}}`, config.NormalizeKeys(config.SnakeCase))
+	assert.True(t, errors.Is(err, config.ErrInvalidValue))
+	assert.EqualError(t, err, `config: Keys "maxConns" and "max_conns" both normalize to "db.max_conns"`)
+	_, err = config.ParseJSON(`{"db.main": {"maxConns": 1, "max_conns": 2}}`, config.NormalizeKeys(config.SnakeCase))
+	assert.EqualError(t, err, `config: Keys "maxConns" and "max_conns" both normalize to "db\\.main.max_conns"`)
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+)
+
+// Lazy defers decoding for large documents that are only partly read. The
+// top level is split into its keys at parse time, but each value is kept as
+// raw JSON until a path under it is first looked up; it is then decoded once
+// and cached. Sections that are never looked up are never decoded. Anything
+// that needs the whole tree, such as Walk, Raw, Merge or a mutation, decodes
+// every remaining section first. Decoding is safe for concurrent use.
+//
+// Lazy applies to JSON documents; a syntax error anywhere is still reported
+// at parse time. It has no effect together with NormalizeKeys, which must see
+// every key to detect collisions, or while a decoder is registered with
+// RegisterDecoder, whose errors are reported at parse time.
+func Lazy() Option {
+	return func(o *options) {
+		o.lazy = true
+	}
+}
+
+func parseJSONLazy(data []byte, o options) (*ConfigImpl, error) {
+	var top map[string]json.RawMessage
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if err := dec.Decode(&top); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("config: unexpected data after JSON document")
+	}
+	c := &ConfigImpl{opts: o, root: map[string]interface{}{}, origin: SourceInline}
+	if len(top) > 0 {
+		c.pending = top
+	}
+	if err := c.checkBounds(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// section returns a root in which the top-level key named by parts, if any,
+// is decoded. Lookups of other sections leave them pending.
+func (c *ConfigImpl) section(parts []string) map[string]interface{} {
+	c.mu.RLock()
+	root, lazy := c.root, len(c.pending) > 0
+	c.mu.RUnlock()
+	if !lazy {
+		return root
+	}
+	if isRootPath(parts) {
+		return c.decodePending(nil)
+	}
+	return c.decodePending(func(key string) bool {
+		return key == parts[0] || (c.opts.caseInsensitive && strings.EqualFold(key, parts[0]))
+	})
+}
+
+// decodePending decodes the pending sections whose key satisfies match, or
+// all of them when match is nil, and returns the updated root. The root is
+// copied rather than modified, since readers may hold the previous one.
+func (c *ConfigImpl) decodePending(match func(string) bool) map[string]interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var next map[string]interface{}
+	for key, raw := range c.pending {
+		if match != nil && !match(key) {
+			continue
+		}
+		if next == nil {
+			next = make(map[string]interface{}, len(c.root)+1)
+			for k, v := range c.root {
+				next[k] = v
+			}
+		}
+		var v interface{}
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		if c.opts.useNumber {
+			dec.UseNumber()
+		}
+		dec.Decode(&v) // raw was validated when the document was parsed
+		next[key] = v
+		delete(c.pending, key)
+	}
+	if next != nil {
+		c.root = next
+	}
+	if len(c.pending) == 0 {
+		c.pending = nil
+	}
+	return c.root
+}
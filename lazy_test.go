@@ -0,0 +1,109 @@
+package config_test
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigLazy(t *testing.T) {
+	cfg, err := config.ParseJSONFile("resources/config/default.conf", config.Lazy())
+	if err != nil {
+		t.Fatal(err)
+	}
+	eager, err := config.ParseJSONFile("resources/config/default.conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, "c", cfg.MustString("nested.1.2.3.0.b"))
+			assert.Equal(t, 32, cfg.MustInt("clothes.pants.waist"))
+		}()
+	}
+	wg.Wait()
+	assert.True(t, cfg.Has("debug"))
+	assert.False(t, cfg.Has("missing"))
+	assert.Equal(t, eager.Flatten(), cfg.Flatten())
+	assert.Equal(t, eager.Raw(), cfg.Raw())
+
+	assert.NoError(t, cfg.Set("name", "Jane"))
+	assert.Equal(t, "Jane", cfg.MustString("name"))
+
+	_, err = config.ParseJSON(`{"a": {"b": }}`, config.Lazy())
+	assert.Error(t, err)
+	_, err = config.ParseJSON(`{"a": 1} {}`, config.Lazy())
+	assert.Error(t, err)
+}
+
+func Test_ConfigLazyOptions(t *testing.T) {
+	cfg, err := config.ParseJSON(`{"Server": {"Port": 8080}, "id": 18446744073709551615}`,
+		config.Lazy(), config.CaseInsensitive(), config.UseNumber())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 8080, cfg.MustInt("server.port"))
+	assert.Equal(t, uint64(18446744073709551615), cfg.MustUint64("id"))
+
+	root, err := config.ParseJSON(`{"a": 1, "b": {"c": 2}}`, config.Lazy())
+	assert.NoError(t, err)
+	keys, err := root.Keys("")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, keys)
+
+	_, err = config.ParseJSON(`{"port": 70000}`, config.Lazy(),
+		config.WithBounds(map[string]config.Range{"port": {Min: 1, Max: 65535}}))
+	assert.Error(t, err)
+}
+
+// largeDocument returns a JSON object with n sections of 100 keys each.
+func largeDocument(n int) string {
+	var b strings.Builder
+	b.WriteString("{")
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		fmt.Fprintf(&b, `"section%d": {`, i)
+		for j := 0; j < 100; j++ {
+			if j > 0 {
+				b.WriteString(",")
+			}
+			fmt.Fprintf(&b, `"key%d": {"value": %d, "name": "item %d"}`, j, j, j)
+		}
+		b.WriteString("}")
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+func benchmarkParse(b *testing.B, opts ...config.Option) {
+	doc := largeDocument(200)
+	b.SetBytes(int64(len(doc)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cfg, err := config.ParseJSON(doc, opts...)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if cfg.MustInt("section7.key42.value") != 42 {
+			b.Fatal("wrong value")
+		}
+	}
+}
+
+func Benchmark_ParseJSONEager(b *testing.B) {
+	benchmarkParse(b)
+}
+
+func Benchmark_ParseJSONLazy(b *testing.B) {
+	benchmarkParse(b, config.Lazy())
+}
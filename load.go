@@ -0,0 +1,149 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// discoverExts are the file extensions Discover tries in each directory, in
+// order of preference.
+var discoverExts = []string{".json", ".jsonc", ".ini", ".properties"}
+
+// LoadFiles parses the JSON files in order and deep-merges them left to
+// right, so later files win. The first failure stops loading and is returned
+// wrapped with the name of the offending file.
+func LoadFiles(paths ...string) (Config, error) {
+	return loadFiles(paths, false)
+}
+
+// LoadFilesOptional behaves like LoadFiles but silently skips files that do
+// not exist. Files that exist but fail to read or parse are still an error.
+func LoadFilesOptional(paths ...string) (Config, error) {
+	return loadFiles(paths, true)
+}
+
+// LoadEnv loads the JSON file at basePath and deep-merges the overlay for the
+// environment named by envVar ("APP_ENV" when empty) on top of it. The
+// overlay sits next to the base file with the environment inserted before
+// the extension: with APP_ENV=production, "conf/config.json" is overlaid by
+// "conf/config.production.json". When the variable is unset or empty, or the
+// overlay does not exist, the base config is returned alone. The base file
+// must exist.
+func LoadEnv(basePath string, envVar string) (Config, error) {
+	if envVar == "" {
+		envVar = "APP_ENV"
+	}
+	if _, err := os.Stat(basePath); err != nil {
+		return nil, fmt.Errorf("config: loading %q: %w", basePath, err)
+	}
+	paths := []string{basePath}
+	if env := os.Getenv(envVar); env != "" {
+		ext := filepath.Ext(basePath)
+		paths = append(paths, strings.TrimSuffix(basePath, ext)+"."+env+ext)
+	}
+	return loadFiles(paths, true)
+}
+
+// LoadWithDefault parses defaultJSON, typically a file embedded with
+// go:embed, and deep-merges the JSON file at overridePath on top of it. When
+// overridePath is empty or does not exist the default is returned alone. A
+// default that does not parse is a bug in the program and is reported as
+// ErrInvalidDefault, distinct from a broken override file.
+func LoadWithDefault(defaultJSON string, overridePath string, opts ...Option) (Config, error) {
+	o := newOptions(opts)
+	def, err := parseJSON([]byte(defaultJSON), o)
+	if err != nil {
+		return nil, newPathError(ErrInvalidDefault, "config: BUG: built-in default config does not parse: %v", err)
+	}
+	if overridePath == "" {
+		return def, nil
+	}
+	over, err := parseJSONFile(overridePath, o)
+	if os.IsNotExist(err) {
+		return def, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config: loading %q: %w", overridePath, err)
+	}
+	root := def.tree()
+	sources := leafSources(root, o.separator(), SourceInline)
+	for leaf, src := range leafSources(over.tree(), o.separator(), fileSource(overridePath)) {
+		sources[leaf] = src
+	}
+	mergeDeep(root, over.tree())
+	return &ConfigImpl{opts: o, root: root, sources: sources}, nil
+}
+
+func loadFiles(paths []string, optional bool) (Config, error) {
+	root := map[string]interface{}{}
+	sources := map[string]string{}
+	for _, path := range paths {
+		cfg, err := parseJSONFile(path, newOptions(nil))
+		if err != nil {
+			if optional && os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("config: loading %q: %w", path, err)
+		}
+		mergeDeep(root, cfg.root)
+		for leaf, src := range leafSources(cfg.root, defaultSeparator, fileSource(path)) {
+			sources[leaf] = src
+		}
+	}
+	return &ConfigImpl{opts: newOptions(nil), root: root, sources: sources}, nil
+}
+
+// Discover finds and loads the config file for the application name the way
+// command line tools do. It searches dirs in order, by default the current
+// directory, $HOME/.config/<name> and /etc/<name>, for <name>.json,
+// <name>.jsonc, <name>.ini and <name>.properties, and loads the first file
+// that exists with the matching parser. It returns the path of the file it
+// used. When no file exists the ErrNotFound error lists every location
+// searched; a file that exists but fails to load is reported as such.
+func Discover(name string, dirs ...string) (Config, string, error) {
+	if len(dirs) == 0 {
+		dirs = []string{"."}
+		if home, err := os.UserHomeDir(); err == nil {
+			dirs = append(dirs, filepath.Join(home, ".config", name))
+		}
+		dirs = append(dirs, filepath.Join("/etc", name))
+	}
+	var searched []string
+	for _, dir := range dirs {
+		for _, ext := range discoverExts {
+			path := filepath.Join(dir, name+ext)
+			searched = append(searched, path)
+			if _, err := os.Stat(path); err != nil {
+				continue
+			}
+			cfg, err := LoadFrom(FileSource{Path: path})
+			if err != nil {
+				return nil, path, fmt.Errorf("config: loading %q: %w", path, err)
+			}
+			return cfg, path, nil
+		}
+	}
+	return nil, "", newPathError(ErrNotFound, "config: no config file for %q; searched:\n  %s", name, strings.Join(searched, "\n  "))
+}
@@ -0,0 +1,142 @@
+package config_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigLoadFiles(t *testing.T) {
+	cfg, err := config.LoadFiles(
+		"resources/config/default.conf",
+		"resources/config/production.conf",
+		"resources/config/local.conf",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "production", cfg.MustString("env"))
+	assert.Equal(t, "John", cfg.MustString("name"))
+	assert.Equal(t, 34, cfg.MustInt("clothes.pants.waist"))
+	assert.Equal(t, 32, cfg.MustInt("clothes.pants.height"))
+	assert.Equal(t, []interface{}{"chess"}, cfg.MustList("hobbies"))
+
+	_, err = config.LoadFiles("resources/config/default.conf", "resources/config/broken.conf")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "resources/config/broken.conf")
+
+	_, err = config.LoadFiles("resources/config/missing.conf")
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func Test_ConfigLoadFilesOptional(t *testing.T) {
+	cfg, err := config.LoadFilesOptional(
+		"resources/config/default.conf",
+		"resources/config/missing.conf",
+		"resources/config/production.conf",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "production", cfg.MustString("env"))
+
+	_, err = config.LoadFilesOptional("resources/config/missing.conf", "resources/config/broken.conf")
+	assert.Error(t, err)
+}
+
+func Test_ConfigDiscover(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	write := func(path, data string) {
+		if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(second, "app.ini"), "[server]\nport = 8080\n")
+
+	cfg, used, err := config.Discover("app", first, second)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(second, "app.ini"), used)
+	assert.Equal(t, 8080, cfg.MustInt("server.port"))
+
+	write(filepath.Join(first, "app.json"), `{"server": {"port": 9090}}`)
+	cfg, used, err = config.Discover("app", first, second)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(first, "app.json"), used)
+	assert.Equal(t, 9090, cfg.MustInt("server.port"))
+
+	_, _, err = config.Discover("other", first, second)
+	assert.True(t, errors.Is(err, config.ErrNotFound))
+	assert.Contains(t, err.Error(), filepath.Join(first, "other.json"))
+	assert.Contains(t, err.Error(), filepath.Join(second, "other.properties"))
+
+	write(filepath.Join(first, "bad.json"), `{`)
+	_, used, err = config.Discover("bad", first)
+	assert.Error(t, err)
+	assert.Equal(t, filepath.Join(first, "bad.json"), used)
+}
+
+func Test_ConfigLoadEnv(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "config.json")
+	files := map[string]string{
+		base: `{"env": "base", "db": {"host": "localhost", "pool": 5}}`,
+		filepath.Join(dir, "config.production.json"): `{"env": "production", "db": {"host": "prod-db"}}`,
+	}
+	for path, data := range files {
+		if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	os.Unsetenv("CONFIG_TEST_ENV")
+	cfg, err := config.LoadEnv(base, "CONFIG_TEST_ENV")
+	assert.NoError(t, err)
+	assert.Equal(t, "base", cfg.MustString("env"))
+
+	defer setenv(t, map[string]string{"CONFIG_TEST_ENV": "production"})()
+	cfg, err = config.LoadEnv(base, "CONFIG_TEST_ENV")
+	assert.NoError(t, err)
+	assert.Equal(t, "production", cfg.MustString("env"))
+	assert.Equal(t, "prod-db", cfg.MustString("db.host"))
+	assert.Equal(t, 5, cfg.MustInt("db.pool"))
+
+	os.Setenv("CONFIG_TEST_ENV", "staging")
+	cfg, err = config.LoadEnv(base, "CONFIG_TEST_ENV")
+	assert.NoError(t, err)
+	assert.Equal(t, "base", cfg.MustString("env"))
+
+	_, err = config.LoadEnv(filepath.Join(dir, "missing.json"), "CONFIG_TEST_ENV")
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func Test_ConfigLoadWithDefault(t *testing.T) {
+	const defaults = `{"port": 8080, "db": {"host": "localhost", "pool": 5}}`
+	path := filepath.Join(t.TempDir(), "app.json")
+
+	cfg, err := config.LoadWithDefault(defaults, path)
+	assert.NoError(t, err)
+	assert.Equal(t, 8080, cfg.MustInt("port"))
+	assert.Equal(t, "localhost", cfg.MustString("db.host"))
+
+	if err := ioutil.WriteFile(path, []byte(`{"db": {"host": "db.internal"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = config.LoadWithDefault(defaults, path)
+	assert.NoError(t, err)
+	assert.Equal(t, 8080, cfg.MustInt("port"))
+	assert.Equal(t, "db.internal", cfg.MustString("db.host"))
+	assert.Equal(t, 5, cfg.MustInt("db.pool"))
+
+	_, err = config.LoadWithDefault(`{"port": `, path)
+	assert.True(t, errors.Is(err, config.ErrInvalidDefault))
+
+	_, err = config.LoadWithDefault(defaults, "resources/config/broken.conf")
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, config.ErrInvalidDefault))
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"reflect"
+)
+
+// GetOrInit returns a value derived from the one at the path, such as a
+// lookup table built from a long list. compute receives the raw value on the
+// first call and its result is cached per path; later calls return the
+// cached result until a Set, reload or other change alters the value at the
+// path. Errors from the lookup or from compute are returned and not cached.
+// Concurrent first calls may each run compute, but only a result computed
+// from the current data is kept.
+func (c *ConfigImpl) GetOrInit(path string, compute func(raw interface{}) (interface{}, error)) (interface{}, error) {
+	c.mu.RLock()
+	v, ok := c.memo[path]
+	gen := c.gen
+	c.mu.RUnlock()
+	if ok {
+		return v, nil
+	}
+
+	raw, err := c.Get(path)
+	if err != nil {
+		return nil, err
+	}
+	v, err = compute(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cached, ok := c.memo[path]; ok {
+		return cached, nil
+	}
+	if c.gen == gen {
+		if c.memo == nil {
+			c.memo = map[string]interface{}{}
+		}
+		c.memo[path] = v
+	}
+	return v, nil
+}
+
+// invalidateMemo drops the cached results whose path changed between old and
+// the current root and starts a new generation. c.mu must be held.
+func (c *ConfigImpl) invalidateMemo(old map[string]interface{}) {
+	c.gen++
+	for path := range c.memo {
+		parts := c.split(path)
+		ov, oerr := fetchParts(old, parts, c.separator())
+		nv, nerr := fetchParts(c.root, parts, c.separator())
+		if (oerr == nil) != (nerr == nil) || !reflect.DeepEqual(ov, nv) {
+			delete(c.memo, path)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package config_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigGetOrInit(t *testing.T) {
+	cfg, err := config.ParseJSON(`{"blocked": ["a", "b"], "other": 1}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls := 0
+	index := func(raw interface{}) (interface{}, error) {
+		calls++
+		set := map[string]bool{}
+		for _, e := range raw.([]interface{}) {
+			set[e.(string)] = true
+		}
+		return set, nil
+	}
+
+	v, err := cfg.GetOrInit("blocked", index)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"a": true, "b": true}, v)
+	v, err = cfg.GetOrInit("blocked", index)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls)
+
+	assert.NoError(t, cfg.Set("other", 2))
+	_, _ = cfg.GetOrInit("blocked", index)
+	assert.Equal(t, 1, calls)
+
+	assert.NoError(t, cfg.Set("blocked.1", "c"))
+	v, err = cfg.GetOrInit("blocked", index)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"a": true, "c": true}, v)
+	assert.Equal(t, 2, calls)
+
+	failure := errors.New("bad list")
+	_, err = cfg.GetOrInit("other", func(interface{}) (interface{}, error) { return nil, failure })
+	assert.True(t, errors.Is(err, failure))
+	v, err = cfg.GetOrInit("other", func(raw interface{}) (interface{}, error) { return raw, nil })
+	assert.NoError(t, err)
+	assert.Equal(t, 2, v)
+
+	_, err = cfg.GetOrInit("missing", index)
+	assert.True(t, errors.Is(err, config.ErrNotFound))
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"reflect"
+)
+
+// MergeOption controls how Merge combines lists present on both sides.
+type MergeOption int
+
+const (
+	// ListReplace replaces the receiver's list with the overlay's.
+	ListReplace MergeOption = iota
+	// ListAppend appends the overlay's elements to the receiver's list.
+	ListAppend
+	// ListByIndex merges the overlay's elements onto the receiver's at the
+	// same index, deep-merging maps, and appends any extra elements.
+	ListByIndex
+)
+
+// Merge returns a new config built from a deep copy of the receiver with the
+// others deep-merged on top in order, later ones winning. Unlike Extend it
+// modifies neither the receiver nor the others. The result keeps the
+// receiver's options, comments and seals, and a merge that would change a
+// sealed subtree fails with ErrSealed. Nil configs are skipped. Lists are
+// replaced whole; see MergeWith for the other list modes.
+func (c *ConfigImpl) Merge(others ...Config) (Config, error) {
+	return c.MergeWith(ListReplace, others...)
+}
+
+// MergeWith is Merge with lists present on both sides combined according to
+// mode.
+func (c *ConfigImpl) MergeWith(mode MergeOption, others ...Config) (Config, error) {
+	next := copyValue(c.tree()).(map[string]interface{})
+	sources := c.leafOrigins()
+	for _, other := range others {
+		if other == nil {
+			continue
+		}
+		root, err := rootOf(other)
+		if err != nil {
+			return nil, err
+		}
+		mergeMaps(next, root, mode)
+		for path, src := range originsOf(other) {
+			// Appended or overlaid list elements may have moved.
+			parts := c.split(path)
+			nv, _ := fetchParts(next, parts, c.separator())
+			ov, _ := fetchParts(root, parts, c.separator())
+			if reflect.DeepEqual(nv, ov) {
+				sources[path] = src
+			}
+		}
+	}
+	if err := c.checkSealed(next); err != nil {
+		return nil, err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := &ConfigImpl{
+		opts:    c.opts,
+		root:    next,
+		sealed:  append([]string{}, c.sealed...),
+		origin:  c.origin,
+		sources: sources,
+	}
+	if c.comments != nil {
+		out.comments = make(map[string]string, len(c.comments))
+		for path, text := range c.comments {
+			out.comments[path] = text
+		}
+	}
+	return out, nil
+}
+
+// MergeReport is Merge that also reports where a later config overrode a
+// different value set by an earlier one, for catching accidental overrides
+// between layered files. Each overridden leaf is a Modified Change, or a
+// Removed one when it was replaced by a subtree or dropped from a replaced
+// list, with Old the value it had before that layer was merged. Changes are
+// listed layer by layer, sorted by path within each layer. Leaves that a
+// layer only adds, or sets to the value they already had, are not reported.
+func (c *ConfigImpl) MergeReport(others ...Config) (Config, []Change, error) {
+	cur, err := c.Merge()
+	if err != nil {
+		return nil, nil, err
+	}
+	var conflicts []Change
+	for _, other := range others {
+		if other == nil {
+			continue
+		}
+		next, err := cur.Merge(other)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, change := range Diff(cur, next) {
+			if change.Kind != Added {
+				conflicts = append(conflicts, change)
+			}
+		}
+		cur = next
+	}
+	return cur, conflicts, nil
+}
+
+// mergeDeep merges src into dst recursively. Maps present on both sides are
+// merged key by key; any other value from src replaces the one in dst. Values
+// taken from src are deep-copied so dst never aliases it.
+func mergeDeep(dst, src map[string]interface{}) {
+	mergeMaps(dst, src, ListReplace)
+}
+
+// mergeMaps is mergeDeep with lists on both sides combined according to mode.
+func mergeMaps(dst, src map[string]interface{}, mode MergeOption) {
+	for k, v := range src {
+		if existing, ok := dst[k]; ok {
+			dst[k] = mergeValue(existing, v, mode)
+			continue
+		}
+		dst[k] = copyValue(v)
+	}
+}
+
+func mergeValue(dst, src interface{}, mode MergeOption) interface{} {
+	switch s := src.(type) {
+	case map[string]interface{}:
+		if d, ok := dst.(map[string]interface{}); ok {
+			mergeMaps(d, s, mode)
+			return d
+		}
+	case []interface{}:
+		d, ok := dst.([]interface{})
+		if !ok {
+			break
+		}
+		switch mode {
+		case ListAppend:
+			return append(d, copyValue(s).([]interface{})...)
+		case ListByIndex:
+			for i, e := range s {
+				if i < len(d) {
+					d[i] = mergeValue(d[i], e, mode)
+				} else {
+					d = append(d, copyValue(e))
+				}
+			}
+			return d
+		}
+	}
+	return copyValue(src)
+}
+
+// copyValue deep-copies maps and lists; scalars are returned as is.
+func copyValue(v interface{}) interface{} {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(x))
+		for k, e := range x {
+			m[k] = copyValue(e)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(x))
+		for i, e := range x {
+			l[i] = copyValue(e)
+		}
+		return l
+	}
+	return v
+}
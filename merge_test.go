@@ -0,0 +1,111 @@
+package config_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigMerge(t *testing.T) {
+	base, err := config.ParseJSONFile("resources/config/default.conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	prod, err := config.ParseJSONFile("resources/config/production.conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	local, err := config.ParseJSONFile("resources/config/local.conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	merged, err := base.Merge(prod, nil, local)
+	assert.NoError(t, err)
+	assert.Equal(t, "production", merged.MustString("env"))
+	assert.Equal(t, 34, merged.MustInt("clothes.pants.waist"))
+	assert.Equal(t, 32, merged.MustInt("clothes.pants.height"))
+	assert.Equal(t, "large", merged.MustString("clothes.size"))
+	assert.Equal(t, []interface{}{"chess"}, merged.MustList("hobbies"))
+
+	assert.Equal(t, "default", base.MustString("env"))
+	assert.Equal(t, 32, base.MustInt("clothes.pants.waist"))
+	assert.False(t, prod.Has("clothes"))
+	assert.False(t, local.Has("env"))
+
+	m, _ := merged.Map("clothes.pants")
+	m["waist"] = 40.0
+	assert.Equal(t, 34, local.MustInt("clothes.pants.waist"))
+
+	base.Seal("env")
+	_, err = base.Merge(prod)
+	assert.True(t, errors.Is(err, config.ErrSealed))
+}
+
+func Test_ConfigMergeWithListModes(t *testing.T) {
+	base, err := config.ParseJSON(`{"plugins": [{"name": "auth", "on": true}, {"name": "gzip"}], "tags": ["a", "b"]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	overlay, err := config.ParseJSON(`{"plugins": [{"on": false}, {"level": 5}, {"name": "trace"}], "tags": ["c"]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replaced, err := base.MergeWith(config.ListReplace, overlay)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"c"}, replaced.MustList("tags"))
+	assert.Len(t, replaced.MustList("plugins"), 3)
+	assert.False(t, replaced.Has("plugins.0.name"))
+
+	appended, err := base.MergeWith(config.ListAppend, overlay)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"a", "b", "c"}, appended.MustList("tags"))
+	assert.Len(t, appended.MustList("plugins"), 5)
+	assert.Equal(t, "trace", appended.MustString("plugins.4.name"))
+
+	indexed, err := base.MergeWith(config.ListByIndex, overlay)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"c", "b"}, indexed.MustList("tags"))
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "auth", "on": false},
+		map[string]interface{}{"name": "gzip", "level": 5.0},
+		map[string]interface{}{"name": "trace"},
+	}, indexed.MustList("plugins"))
+
+	assert.Equal(t, []interface{}{"a", "b"}, base.MustList("tags"))
+	assert.Len(t, base.MustList("plugins"), 2)
+	assert.True(t, base.MustBool("plugins.0.on"))
+	assert.Len(t, overlay.MustList("plugins"), 3)
+}
+
+func Test_ConfigMergeReport(t *testing.T) {
+	base, err := config.ParseJSON(`{"db": {"host": "a", "port": 5432}, "name": "api"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	team, err := config.ParseJSON(`{"db": {"host": "b", "port": 5432, "pool": 10}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	local, err := config.ParseJSON(`{"name": {"first": "api"}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	merged, changes, err := base.MergeReport(team, nil, local)
+	assert.NoError(t, err)
+	assert.Equal(t, "b", merged.MustString("db.host"))
+	assert.Equal(t, 10, merged.MustInt("db.pool"))
+	assert.Equal(t, []config.Change{
+		{Path: "db.host", Old: "a", New: "b", Kind: config.Modified},
+		{Path: "name", Old: "api", Kind: config.Removed},
+	}, changes)
+	assert.Equal(t, "a", base.MustString("db.host"))
+
+	_, changes, err = base.MergeReport()
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+}
@@ -0,0 +1,211 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"errors"
+	"strconv"
+)
+
+// Set stores a deep copy of value at the path, creating missing maps along
+// the way. A list element can be replaced by index but lists are not grown.
+// Like the other mutations it fails with ErrSealed inside a sealed subtree,
+// notifies OnChange watchers and is applied one at a time with concurrent
+// mutations, so none of them is lost.
+func (c *ConfigImpl) Set(path string, value interface{}) error {
+	defer c.beginWrite()()
+	return c.set(path, value)
+}
+
+func (c *ConfigImpl) set(path string, value interface{}) error {
+	root := copyValue(c.tree()).(map[string]interface{})
+	parts := c.split(path)
+	if isRootPath(parts) {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return newPathError(ErrWrongType, "config: Root value must be a map, got %T", value)
+		}
+		return c.commit(copyValue(m).(map[string]interface{}))
+	}
+	var node interface{} = root
+	for pos, part := range parts {
+		last := pos == len(parts)-1
+		curPath := c.join(parts[:pos+1]...)
+		switch x := node.(type) {
+		case map[string]interface{}:
+			if last {
+				x[part] = copyValue(value)
+				break
+			}
+			next, ok := x[part]
+			if !ok {
+				next = map[string]interface{}{}
+				x[part] = next
+			}
+			node = next
+		case []interface{}:
+			ix, err := strconv.Atoi(part)
+			if err != nil || ix < 0 || ix >= len(x) {
+				return newPathError(ErrNotFound, "config: Index out of bound at %q", curPath)
+			}
+			if last {
+				x[ix] = copyValue(value)
+				break
+			}
+			node = x[ix]
+		default:
+			return newPathError(ErrWrongType, "config: Unknown type at %q", curPath)
+		}
+	}
+	return c.commit(root)
+}
+
+// Delete removes the value at the path. Removing a list element shifts the
+// elements after it down. A path that does not resolve is ErrNotFound; keys
+// are matched exactly, even with CaseInsensitive.
+func (c *ConfigImpl) Delete(path string) error {
+	defer c.beginWrite()()
+	parts := c.split(path)
+	if isRootPath(parts) {
+		return newPathError(ErrInvalidValue, "config: Cannot delete the root")
+	}
+	if _, err := fetchParts(c.tree(), parts, c.separator()); err != nil {
+		return err
+	}
+	root := copyValue(c.tree()).(map[string]interface{})
+	return c.commit(without(root, parts).(map[string]interface{}))
+}
+
+// Append adds deep copies of values to the end of the list at the path. A
+// missing path gets a new list, created like Set creates missing maps; a
+// value other than a list is ErrWrongType.
+func (c *ConfigImpl) Append(path string, values ...interface{}) error {
+	defer c.beginWrite()()
+	var list []interface{}
+	x, err := fetchParts(c.tree(), c.split(path), c.separator())
+	switch {
+	case errors.Is(err, ErrNotFound):
+	case err != nil:
+		return err
+	default:
+		var ok bool
+		if list, ok = x.([]interface{}); !ok {
+			return newPathError(ErrWrongType, "config: Cannot append to non-list at %q", path)
+		}
+	}
+	next := make([]interface{}, 0, len(list)+len(values))
+	next = append(append(next, list...), values...)
+	return c.set(path, next)
+}
+
+// without returns node with the value at parts, which must exist, removed.
+func without(node interface{}, parts []string) interface{} {
+	switch x := node.(type) {
+	case map[string]interface{}:
+		if len(parts) == 1 {
+			delete(x, parts[0])
+		} else {
+			x[parts[0]] = without(x[parts[0]], parts[1:])
+		}
+		return x
+	case []interface{}:
+		ix, _ := strconv.Atoi(parts[0])
+		if len(parts) == 1 {
+			return append(x[:ix:ix], x[ix+1:]...)
+		}
+		x[ix] = without(x[ix], parts[1:])
+		return x
+	}
+	return node
+}
+
+// Snapshot records a deep copy of the current data and returns a function
+// that restores it. Defer the restore before a series of changes and skip it
+// once they have all succeeded:
+//
+//	restore := cfg.Snapshot()
+//	defer func() {
+//		if !ok {
+//			restore()
+//		}
+//	}()
+//
+// Restoring bypasses seals, since it returns to data that satisfied them,
+// and notifies OnChange watchers of the values it changes back.
+func (c *ConfigImpl) Snapshot() func() {
+	saved := copyValue(c.tree()).(map[string]interface{})
+	return func() {
+		defer c.beginWrite()()
+		c.replace(copyValue(saved).(map[string]interface{}))
+	}
+}
+
+// Clone returns an independent deep copy of the config with the same options,
+// annotations, seals and provenance. Watchers and reload hooks are not
+// copied.
+func (c *ConfigImpl) Clone() Config {
+	root := c.tree()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := &ConfigImpl{
+		opts:       c.opts,
+		root:       copyValue(root).(map[string]interface{}),
+		sealed:     append([]string(nil), c.sealed...),
+		origin:     c.origin,
+		deprecated: append([]deprecation(nil), c.deprecated...),
+	}
+	if c.comments != nil {
+		out.comments = make(map[string]string, len(c.comments))
+		for path, text := range c.comments {
+			out.comments[path] = text
+		}
+	}
+	if c.sources != nil {
+		out.sources = make(map[string]string, len(c.sources))
+		for path, src := range c.sources {
+			out.sources[path] = src
+		}
+	}
+	return out
+}
+
+// Transaction runs fn against a private copy of the config and, if fn
+// returns nil, replaces the data with the copy in one step. Readers never
+// see a partial update, and on error nothing changes. The copy shares the
+// receiver's options and seals but not its watchers, which are notified once
+// when the result is committed. Other changes to the receiver wait until the
+// transaction ends, so none of them is lost; fn must therefore make its
+// changes through the copy it is given, not through the receiver.
+func (c *ConfigImpl) Transaction(fn func(Config) error) error {
+	defer c.beginWrite()()
+	root := c.tree()
+	c.mu.RLock()
+	tx := &ConfigImpl{
+		opts:   c.opts,
+		root:   copyValue(root).(map[string]interface{}),
+		sealed: append([]string{}, c.sealed...),
+	}
+	c.mu.RUnlock()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return c.commit(tx.tree())
+}
@@ -0,0 +1,159 @@
+package config_test
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigSetDelete(t *testing.T) {
+	cfg, err := config.ParseJSON(`{"server": {"port": 80}, "hosts": ["a", "b", "c"], "name": "api"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NoError(t, cfg.Set("server.port", 8080))
+	assert.Equal(t, 8080, cfg.MustInt("server.port"))
+	assert.NoError(t, cfg.Set("server.tls.cert", "site.pem"))
+	assert.Equal(t, "site.pem", cfg.MustString("server.tls.cert"))
+	assert.NoError(t, cfg.Set("hosts.1", "B"))
+	assert.Equal(t, "B", cfg.MustString("hosts.1"))
+
+	assert.True(t, errors.Is(cfg.Set("hosts.5", "x"), config.ErrNotFound))
+	assert.True(t, errors.Is(cfg.Set("name.first", "x"), config.ErrWrongType))
+
+	assert.NoError(t, cfg.Delete("hosts.0"))
+	assert.Equal(t, []interface{}{"B", "c"}, cfg.MustList("hosts"))
+	assert.NoError(t, cfg.Delete("server.tls"))
+	assert.False(t, cfg.Has("server.tls"))
+	assert.True(t, errors.Is(cfg.Delete("server.tls"), config.ErrNotFound))
+
+	cfg.Seal("server")
+	assert.True(t, errors.Is(cfg.Set("server.port", 1), config.ErrSealed))
+	assert.True(t, errors.Is(cfg.Delete("server.port"), config.ErrSealed))
+}
+
+func Test_ConfigSetDeleteRoot(t *testing.T) {
+	cfg, err := config.ParseJSON(`{"server": {"port": 80}, "name": "api"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.True(t, errors.Is(cfg.Set("", "x"), config.ErrWrongType))
+	assert.NoError(t, cfg.Set("", map[string]interface{}{"name": "web"}))
+	assert.Equal(t, map[string]interface{}{"name": "web"}, cfg.Raw())
+	assert.False(t, cfg.Has("server"))
+
+	err = cfg.Delete("")
+	assert.True(t, errors.Is(err, config.ErrInvalidValue))
+	assert.EqualError(t, err, "config: Cannot delete the root")
+	assert.Equal(t, "web", cfg.MustString("name"))
+}
+
+func Test_ConfigAppend(t *testing.T) {
+	cfg, err := config.ParseJSON(`{"hosts": ["a"], "name": "api"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NoError(t, cfg.Append("hosts", "b", "c"))
+	assert.Equal(t, []interface{}{"a", "b", "c"}, cfg.MustList("hosts"))
+	assert.NoError(t, cfg.Append("discovered.peers", map[string]interface{}{"addr": "10.0.0.1"}))
+	assert.NoError(t, cfg.Append("discovered.peers", map[string]interface{}{"addr": "10.0.0.2"}))
+	assert.Equal(t, "10.0.0.2", cfg.MustString("discovered.peers.1.addr"))
+	assert.NoError(t, cfg.Append("empty"))
+	assert.Equal(t, []interface{}{}, cfg.MustList("empty"))
+
+	assert.True(t, errors.Is(cfg.Append("name", "x"), config.ErrWrongType))
+	assert.True(t, errors.Is(cfg.Append("name.first", "x"), config.ErrWrongType))
+	cfg.Seal("hosts")
+	assert.True(t, errors.Is(cfg.Append("hosts", "d"), config.ErrSealed))
+	assert.True(t, errors.Is(cfg.Freeze().Append("discovered.peers", "x"), config.ErrReadOnly))
+}
+
+func Test_ConfigTransaction(t *testing.T) {
+	cfg, err := config.ParseJSON(`{"db": {"host": "a", "port": 5432}, "replicas": 2}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var changes int
+	cfg.OnChange("db.host", func(old, new interface{}) { changes++ })
+
+	failure := errors.New("replica check failed")
+	err = cfg.Transaction(func(tx config.Config) error {
+		if err := tx.Set("db.host", "b"); err != nil {
+			return err
+		}
+		if err := tx.Delete("replicas"); err != nil {
+			return err
+		}
+		return failure
+	})
+	assert.True(t, errors.Is(err, failure))
+	assert.Equal(t, "a", cfg.MustString("db.host"))
+	assert.Equal(t, 2, cfg.MustInt("replicas"))
+	assert.Equal(t, 0, changes)
+
+	err = cfg.Transaction(func(tx config.Config) error {
+		if err := tx.Set("db.host", "b"); err != nil {
+			return err
+		}
+		return tx.Set("db.port", 6432)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "b", cfg.MustString("db.host"))
+	assert.Equal(t, 6432, cfg.MustInt("db.port"))
+	assert.Equal(t, 1, changes)
+
+	restore := cfg.Snapshot()
+	assert.NoError(t, cfg.Set("db.host", "c"))
+	assert.Error(t, cfg.Set("db.port.x", 1))
+	restore()
+	assert.Equal(t, "b", cfg.MustString("db.host"))
+	assert.Equal(t, 3, changes)
+}
+
+func Test_ConfigConcurrentSet(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(8))
+	cfg, err := config.ParseJSON(`{"counts": {}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var notified int
+	var mu sync.Mutex
+	cfg.OnChange("last", func(old, new interface{}) {
+		mu.Lock()
+		notified++
+		mu.Unlock()
+		// Callbacks run after the writer lock is released.
+		assert.NoError(t, cfg.Set("seen", true))
+	})
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				assert.NoError(t, cfg.Set(fmt.Sprintf("counts.g%d_%d", g, i), i))
+				assert.NoError(t, cfg.Append("log", g))
+			}
+			assert.NoError(t, cfg.Transaction(func(tx config.Config) error {
+				return tx.Set("last", g)
+			}))
+		}(g)
+	}
+	wg.Wait()
+
+	counts, err := cfg.Map("counts")
+	assert.NoError(t, err)
+	assert.Len(t, counts, 160)
+	assert.Len(t, cfg.MustList("log"), 160)
+	assert.True(t, cfg.MustBool("seen"))
+	assert.Greater(t, notified, 0)
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"net/http"
+	"time"
+)
+
+type (
+	// Option customizes how a config is parsed and accessed.
+	Option func(*options)
+
+	options struct {
+		sep             string
+		redact          []string
+		stringCoercion  bool
+		bounds          map[string]Range
+		base64Fallback  bool
+		caseInsensitive bool
+		useNumber       bool
+		negativeIndices bool
+		strictKeys      bool
+		lazy            bool
+		keyStyle        KeyStyle
+		defaultsKey     string
+		schema          SchemaValidator
+		httpClient      *http.Client
+		httpTimeout     time.Duration
+	}
+
+	// Range is an inclusive numeric interval used by WithBounds.
+	Range struct {
+		Min, Max float64
+	}
+)
+
+// newParsed builds a config from freshly parsed data and runs the parse-time
+// checks selected by the options.
+func newParsed(root map[string]interface{}, o options) (*ConfigImpl, error) {
+	if o.keyStyle != KeepKeys {
+		norm, err := normalizeKeys(root, "", o)
+		if err != nil {
+			return nil, err
+		}
+		root, _ = norm.(map[string]interface{})
+	}
+	if err := applyDecoders(root, "", o.separator()); err != nil {
+		return nil, err
+	}
+	c := &ConfigImpl{opts: o, root: root, origin: SourceInline}
+	if err := c.checkBounds(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// separator returns the path separator, defaulting to ".".
+func (o options) separator() string {
+	if o.sep == "" {
+		return defaultSeparator
+	}
+	return o.sep
+}
+
+func newOptions(opts []Option) options {
+	o := options{sep: defaultSeparator}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithSeparator splits paths on sep instead of ".". An empty separator keeps
+// the default.
+func WithSeparator(sep string) Option {
+	return func(o *options) {
+		if sep != "" {
+			o.sep = sep
+		}
+	}
+}
+
+// WithStringCoercion makes String accept numbers and bools, rendering them
+// as strconv does ("8080", "5.1", "true") instead of failing.
+func WithStringCoercion() Option {
+	return func(o *options) {
+		o.stringCoercion = true
+	}
+}
+
+// WithBounds fails parsing unless each listed path that is present holds a
+// number within its Range. All violations are reported in one error.
+func WithBounds(bounds map[string]Range) Option {
+	return func(o *options) {
+		o.bounds = bounds
+	}
+}
+
+// WithBase64Fallback lets Base64 accept unpadded and URL-safe encodings when
+// a value is not valid standard base64.
+func WithBase64Fallback() Option {
+	return func(o *options) {
+		o.base64Fallback = true
+	}
+}
+
+// CaseInsensitive makes path lookups match map keys regardless of case. A key
+// that matches exactly is always preferred. Otherwise exactly one key must
+// fold to the segment: when several do, such as "Server" and "SERVER" for
+// "server", the lookup fails with ErrInvalidValue rather than picking one.
+// Query, watchers and seals still compare keys exactly.
+func CaseInsensitive() Option {
+	return func(o *options) {
+		o.caseInsensitive = true
+	}
+}
+
+// UseNumber decodes JSON numbers as json.Number rather than float64, so
+// integers such as 64-bit IDs keep every digit. The getters understand
+// json.Number, but values handed out by Get, Map, List or Raw hold it
+// instead of float64, so code type-switching on float64 must handle both.
+func UseNumber() Option {
+	return func(o *options) {
+		o.useNumber = true
+	}
+}
+
+// NegativeIndices lets list indices count from the end, so "hobbies.-1" is
+// the last hobby. Without it a negative index is reported as not found.
+func NegativeIndices() Option {
+	return func(o *options) {
+		o.negativeIndices = true
+	}
+}
+
+// StrictKeys fails parsing when an object repeats a key, which encoding/json
+// otherwise resolves silently by keeping the last value. The error names the
+// duplicated key and its path.
+func StrictKeys() Option {
+	return func(o *options) {
+		o.strictKeys = true
+	}
+}
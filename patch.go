@@ -0,0 +1,99 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"errors"
+	"reflect"
+)
+
+// Patch returns a JSON merge patch (RFC 7386) that turns the receiver into
+// target: changed leaves and added keys carry their new value, nested maps
+// are diffed recursively, lists are replaced whole and removed keys are set
+// to nil. As in RFC 7386, a nil value in target cannot be told apart from a
+// removal.
+func (c *ConfigImpl) Patch(target Config) (map[string]interface{}, error) {
+	to, err := rootOf(target)
+	if err != nil {
+		return nil, err
+	}
+	return mergePatch(c.tree(), to), nil
+}
+
+// ApplyPatch applies a JSON merge patch such as the one returned by Patch:
+// nil values delete keys, maps are merged recursively and any other value
+// replaces the current one.
+func (c *ConfigImpl) ApplyPatch(patch map[string]interface{}) error {
+	defer c.beginWrite()()
+	root := copyValue(c.tree()).(map[string]interface{})
+	applyMergePatch(root, patch)
+	return c.commit(root)
+}
+
+// rootOf returns the data behind a Config.
+func rootOf(cfg Config) (map[string]interface{}, error) {
+	if cfg == nil {
+		return nil, errors.New("config: nil Config")
+	}
+	return cfg.Raw(), nil
+}
+
+func mergePatch(from, to map[string]interface{}) map[string]interface{} {
+	patch := map[string]interface{}{}
+	for k := range from {
+		if _, ok := to[k]; !ok {
+			patch[k] = nil
+		}
+	}
+	for k, tv := range to {
+		fv, ok := from[k]
+		if ok && reflect.DeepEqual(fv, tv) {
+			continue
+		}
+		fm, fok := fv.(map[string]interface{})
+		tm, tok := tv.(map[string]interface{})
+		if fok && tok {
+			patch[k] = mergePatch(fm, tm)
+			continue
+		}
+		patch[k] = copyValue(tv)
+	}
+	return patch
+}
+
+func applyMergePatch(dst, patch map[string]interface{}) {
+	for k, v := range patch {
+		if v == nil {
+			delete(dst, k)
+			continue
+		}
+		if pm, ok := v.(map[string]interface{}); ok {
+			dm, ok := dst[k].(map[string]interface{})
+			if !ok {
+				dm = map[string]interface{}{}
+				dst[k] = dm
+			}
+			applyMergePatch(dm, pm)
+			continue
+		}
+		dst[k] = copyValue(v)
+	}
+}
@@ -0,0 +1,32 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigPatch(t *testing.T) {
+	cfg, err := config.ParseJSONFile("resources/config/default.conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	target, err := config.ParseJSONFile("resources/config/stagging.conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	patch, err := cfg.Patch(target)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"env":    "stagging",
+		"nested": nil,
+		"clothes": map[string]interface{}{
+			"pants": map[string]interface{}{"waist": 32.1, "height": 32.1},
+		},
+	}, patch)
+
+	assert.NoError(t, cfg.ApplyPatch(patch))
+	assert.Equal(t, target.Flatten(), cfg.Flatten())
+}
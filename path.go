@@ -0,0 +1,133 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"sort"
+	"strings"
+)
+
+// Paths are dotted by default: each "." separates two segments. A literal
+// separator inside a key is escaped with a backslash and a literal backslash
+// is written as `\\`, so the key "log.level" is reached with `log\.level`.
+// WithSeparator selects another separator; list indices are plain integer
+// segments whatever the separator, e.g. "servers/0/host" with "/".
+const defaultSeparator = "."
+
+// JoinPath builds a dotted path from raw key segments, escaping dots and
+// backslashes inside each segment.
+func JoinPath(segments ...string) string {
+	return joinSegments(segments, defaultSeparator)
+}
+
+// Keys returns the sorted keys of the map at the path, escaped so they can
+// be appended to a path. An empty path lists the top-level keys.
+func (c *ConfigImpl) Keys(path string) ([]string, error) {
+	x, err := c.Get(path)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := x.(map[string]interface{})
+	if !ok {
+		return nil, wrongType(x, path)
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, escapeSegment(k, c.separator()))
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (c *ConfigImpl) separator() string {
+	return c.opts.separator()
+}
+
+// childPath returns the path of key inside the map at path.
+func (c *ConfigImpl) childPath(path, key string) string {
+	return joinPath(path, key, c.separator())
+}
+
+// split splits a path using the configured separator.
+func (c *ConfigImpl) split(path string) []string {
+	return splitPath(strings.TrimSpace(path), c.separator())
+}
+
+// join builds a path from raw segments using the configured separator.
+func (c *ConfigImpl) join(segments ...string) string {
+	return joinSegments(segments, c.separator())
+}
+
+// isRootPath reports whether parts, as returned by split, name the root: an
+// empty path splits into a single empty segment.
+func isRootPath(parts []string) bool {
+	return len(parts) == 0 || len(parts) == 1 && parts[0] == ""
+}
+
+// splitPath splits a path into raw segments, honoring escapes.
+func splitPath(path, sep string) []string {
+	parts := []string{}
+	var seg strings.Builder
+	for i := 0; i < len(path); i++ {
+		rest := path[i:]
+		switch {
+		case strings.HasPrefix(rest, `\\`):
+			i++
+			seg.WriteByte('\\')
+		case strings.HasPrefix(rest, `\`+sep):
+			i += len(sep)
+			seg.WriteString(sep)
+		case strings.HasPrefix(rest, sep):
+			i += len(sep) - 1
+			parts = append(parts, seg.String())
+			seg.Reset()
+		default:
+			seg.WriteByte(path[i])
+		}
+	}
+	return append(parts, seg.String())
+}
+
+// escapeSegment escapes a raw key so it survives splitPath unchanged.
+func escapeSegment(segment, sep string) string {
+	if !strings.Contains(segment, sep) && !strings.Contains(segment, `\`) {
+		return segment
+	}
+	segment = strings.Replace(segment, `\`, `\\`, -1)
+	return strings.Replace(segment, sep, `\`+sep, -1)
+}
+
+// joinSegments is the inverse of splitPath.
+func joinSegments(segments []string, sep string) string {
+	escaped := make([]string, len(segments))
+	for i, s := range segments {
+		escaped[i] = escapeSegment(s, sep)
+	}
+	return strings.Join(escaped, sep)
+}
+
+// joinPath appends a raw segment to a path.
+func joinPath(prefix, segment, sep string) string {
+	if prefix == "" {
+		return escapeSegment(segment, sep)
+	}
+	return prefix + sep + escapeSegment(segment, sep)
+}
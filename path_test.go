@@ -0,0 +1,124 @@
+package config_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigEscapedPath(t *testing.T) {
+	cfg, err := config.ParseJSON(`{
+		"log.level": "debug",
+		"loggers": {"http.server": {"level": "warn"}, "db": {"level": "info"}}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "debug", cfg.MustString(`log\.level`))
+	assert.Equal(t, "warn", cfg.MustString(`loggers.http\.server.level`))
+	_, err = cfg.String("log.level")
+	assert.Error(t, err)
+
+	keys, err := cfg.Keys("loggers")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"db", `http\.server`}, keys)
+	assert.Equal(t, "warn", cfg.MustString("loggers."+keys[1]+".level"))
+
+	path := config.JoinPath("loggers", "http.server", "level")
+	assert.Equal(t, `loggers.http\.server.level`, path)
+	assert.Equal(t, "warn", cfg.MustString(path))
+}
+
+func Test_ConfigSeparator(t *testing.T) {
+	cfg, err := config.ParseJSON(`{
+		"log.level": "debug",
+		"servers": [{"host": "a"}, {"host": "b:80"}],
+		"paths": {"a/b": 1}
+	}`, config.WithSeparator("/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "debug", cfg.MustString("log.level"))
+	assert.Equal(t, "b:80", cfg.MustString("servers/1/host"))
+	assert.Equal(t, 1, cfg.MustInt(`paths/a\/b`))
+
+	keys, err := cfg.Keys("paths")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`a\/b`}, keys)
+
+	hosts, err := cfg.Query("servers/*/host")
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"a", "b:80"}, hosts)
+
+	cfg, err = config.ParseJSON(`{"db": {"primary": {"port": 5432}}}`, config.WithSeparator("::"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 5432, cfg.MustInt("db::primary::port"))
+}
+
+func Test_ConfigCaseInsensitive(t *testing.T) {
+	doc := `{"Server": {"Port": 80, "port": 81, "HOST": "a"}, "mode": "x", "Mode": "y", "DUP": 1, "Dup": 2}`
+
+	cfg, err := config.ParseJSON(doc)
+	assert.NoError(t, err)
+	_, err = cfg.Int("server.port")
+	assert.True(t, errors.Is(err, config.ErrNotFound))
+
+	cfg, err = config.ParseJSON(doc, config.CaseInsensitive())
+	assert.NoError(t, err)
+	assert.Equal(t, "a", cfg.MustString("server.host"))
+	assert.Equal(t, 81, cfg.MustInt("SERVER.port"))
+	assert.Equal(t, 80, cfg.MustInt("server.Port"))
+	assert.Equal(t, "y", cfg.MustString("Mode"))
+	assert.True(t, cfg.Has("sErVeR"))
+
+	_, err = cfg.Int("dup")
+	assert.True(t, errors.Is(err, config.ErrInvalidValue))
+	assert.EqualError(t, err, `config: Ambiguous keys ["DUP" "Dup"] at "dup"`)
+	_, err = cfg.String("server.missing")
+	assert.True(t, errors.Is(err, config.ErrNotFound))
+}
+
+func Test_ConfigListIndexBounds(t *testing.T) {
+	doc := `{"hobbies": ["skateboard", "snowboard", "go"]}`
+	cfg, err := config.ParseJSON(doc)
+	assert.NoError(t, err)
+
+	for _, path := range []string{"hobbies.-1", "hobbies.-4", "hobbies.3", "hobbies.99999999999", "hobbies.99999999999999999999", "hobbies.first"} {
+		assert.NotPanics(t, func() {
+			_, err = cfg.String(path)
+		}, path)
+		assert.Error(t, err, path)
+	}
+	_, err = cfg.String("hobbies.-1")
+	assert.True(t, errors.Is(err, config.ErrNotFound))
+	assert.EqualError(t, err, `config: Negative index at "hobbies.-1"`)
+
+	cfg, err = config.ParseJSON(doc, config.NegativeIndices())
+	assert.NoError(t, err)
+	assert.Equal(t, "go", cfg.MustString("hobbies.-1"))
+	assert.Equal(t, "skateboard", cfg.MustString("hobbies.-3"))
+	_, err = cfg.String("hobbies.-4")
+	assert.True(t, errors.Is(err, config.ErrNotFound))
+}
+
+func Test_ConfigNonIntegerListIndex(t *testing.T) {
+	cfg, err := config.ParseJSON(`{"hobbies": ["skateboard", "snowboard"]}`)
+	assert.NoError(t, err)
+
+	_, err = cfg.String("hobbies.1.5")
+	assert.EqualError(t, err, `config: Unknown type at "hobbies.1.5"`)
+	_, err = cfg.String("hobbies.first")
+	assert.True(t, errors.Is(err, config.ErrWrongType))
+	assert.EqualError(t, err, `config: expected integer list index at "hobbies.first", got "first"`)
+
+	cfg, err = config.ParseJSON(`{"hobbies": ["skateboard", "snowboard"]}`, config.WithSeparator("/"))
+	assert.NoError(t, err)
+	_, err = cfg.String("hobbies/1.5")
+	assert.EqualError(t, err, `config: expected integer list index at "hobbies/1.5", got "1.5"`)
+}
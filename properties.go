@@ -0,0 +1,195 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ParseEnvFile reads a file of KEY=value lines, as used by .env files. Lines
+// may start with "export ". See ParsePropertiesFile for the syntax.
+func ParseEnvFile(path string, opts ...Option) (Config, error) {
+	return parseKeyValueFile(path, true, newOptions(opts))
+}
+
+// ParsePropertiesFile reads a Java-style properties file of key=value lines.
+// Keys are split on "." into a nested tree, so "db.port=5432" is read with
+// Int("db.port"). Blank lines and lines starting with "#" are ignored, as is a
+// trailing " #" comment after an unquoted value. Values may be wrapped in
+// single or double quotes; double-quoted values understand \n, \t, \" and \\.
+// Unquoted true/false and numbers are converted so Bool, Int and Float work,
+// with the values JSON would give them. Integers with a leading zero, such as
+// the file mode 0755, stay strings, as does everything else. A repeated key
+// keeps the last value, and a line without "=" is an error.
+func ParsePropertiesFile(path string, opts ...Option) (Config, error) {
+	return parseKeyValueFile(path, false, newOptions(opts))
+}
+
+func parseKeyValueFile(path string, env bool, o options) (*ConfigImpl, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := parseKeyValues(data, env, o)
+	if err != nil {
+		return nil, err
+	}
+	cfg.origin = fileSource(path)
+	return cfg, nil
+}
+
+func parseKeyValues(data []byte, env bool, o options) (*ConfigImpl, error) {
+	flat := map[string]interface{}{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if env {
+			line = strings.TrimPrefix(line, "export ")
+		}
+		eq := strings.Index(line, "=")
+		if eq < 0 {
+			return nil, fmt.Errorf("config: line %d: missing \"=\" in %q", n, line)
+		}
+		key := strings.TrimSpace(line[:eq])
+		if key == "" {
+			return nil, fmt.Errorf("config: line %d: empty key", n)
+		}
+		value, err := parseRawValue(strings.TrimSpace(line[eq+1:]), "#")
+		if err != nil {
+			return nil, fmt.Errorf("config: line %d: %v", n, err)
+		}
+		flat[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	root, err := unflatten(flat, defaultSeparator)
+	if err != nil {
+		return nil, err
+	}
+	return newParsed(root, o)
+}
+
+// parseRawValue unquotes a value or strips a trailing comment introduced by
+// whitespace and one of the comment characters, then coerces it with
+// coerceScalar.
+func parseRawValue(s, comment string) (interface{}, error) {
+	if s == "" {
+		return "", nil
+	}
+	if q := s[0]; q == '"' || q == '\'' {
+		var out strings.Builder
+		for i := 1; i < len(s); i++ {
+			ch := s[i]
+			if ch == q {
+				rest := strings.TrimSpace(s[i+1:])
+				if rest != "" && !strings.ContainsRune(comment, rune(rest[0])) {
+					return nil, fmt.Errorf("unexpected %q after quoted value", rest)
+				}
+				return out.String(), nil
+			}
+			if ch == '\\' && q == '"' && i+1 < len(s) {
+				i++
+				switch s[i] {
+				case 'n':
+					ch = '\n'
+				case 't':
+					ch = '\t'
+				case 'r':
+					ch = '\r'
+				default:
+					ch = s[i]
+				}
+			}
+			out.WriteByte(ch)
+		}
+		return nil, fmt.Errorf("unterminated quoted value %s", s)
+	}
+	for i := 1; i < len(s); i++ {
+		if strings.IndexByte(comment, s[i]) >= 0 && (s[i-1] == ' ' || s[i-1] == '\t') {
+			s = strings.TrimSpace(s[:i])
+			break
+		}
+	}
+	return coerceScalar(s), nil
+}
+
+// coerceScalar converts the literals true and false to bools and JSON number
+// literals to float64, giving the values the JSON decoder would produce for
+// the same text. An integer with a leading zero, such as the file mode 0755,
+// is not a JSON number: it is an identifier rather than a quantity and stays
+// a string, as does anything else.
+func coerceScalar(s string) interface{} {
+	switch s {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if !isPlainNumber(s) {
+		return s
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil && !math.IsInf(f, 0) {
+		return f
+	}
+	return s
+}
+
+// isPlainNumber reports whether s is a JSON number literal, which rules out
+// leading zeros in its integer part.
+func isPlainNumber(s string) bool {
+	s = strings.TrimPrefix(s, "-")
+	digits := func(s string) int {
+		n := 0
+		for n < len(s) && s[n] >= '0' && s[n] <= '9' {
+			n++
+		}
+		return n
+	}
+	n := digits(s)
+	if n == 0 || n > 1 && s[0] == '0' {
+		return false
+	}
+	s = s[n:]
+	if strings.HasPrefix(s, ".") {
+		if n = digits(s[1:]); n == 0 {
+			return false
+		}
+		s = s[n+1:]
+	}
+	if strings.HasPrefix(s, "e") || strings.HasPrefix(s, "E") {
+		s = strings.TrimLeft(s[1:], "+-")
+		if n = digits(s); n == 0 {
+			return false
+		}
+		s = s[n:]
+	}
+	return s == ""
+}
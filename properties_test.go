@@ -0,0 +1,84 @@
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigParseEnvFile(t *testing.T) {
+	cfg, err := config.ParseEnvFile("resources/config/app.env")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "production", cfg.MustString("APP_ENV"))
+	assert.Equal(t, false, cfg.MustBool("APP_DEBUG", true))
+	assert.Equal(t, 8080, cfg.MustInt("APP_PORT"))
+	assert.Equal(t, "Billing API", cfg.MustString("APP_NAME"))
+	assert.Equal(t, "hello # not a comment", cfg.MustString("APP_GREETING"))
+	assert.Equal(t, "line one\nline two", cfg.MustString("APP_MOTD"))
+}
+
+func Test_ConfigParsePropertiesFile(t *testing.T) {
+	cfg, err := config.ParsePropertiesFile("resources/config/app.properties")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "db.internal", cfg.MustString("db.host"))
+	assert.Equal(t, 5432, cfg.MustInt("db.port"))
+	assert.Equal(t, true, cfg.MustBool("db.ssl"))
+	assert.Equal(t, 2.5, cfg.MustFloat("db.timeout"))
+	assert.Equal(t, []interface{}{"replica-a", "replica-b"}, cfg.MustList("db.replicas"))
+	assert.Equal(t, "1.10", cfg.MustString("app.version"))
+}
+
+func Test_ConfigParsePropertiesNumbers(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "numbers.properties")
+	data := "mode = 0755\nversion = 1.10\nagent = 007\nzero = 0\nratio = 0.50\ntimeout = 1.0\n" +
+		"id = 1234567890123456789\nbig = 1e3\nhalf = .5\n"
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.ParsePropertiesFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "0755", cfg.MustString("mode"))
+	assert.Equal(t, "007", cfg.MustString("agent"))
+	assert.Equal(t, 1.1, cfg.MustFloat("version"))
+	assert.Equal(t, 0, cfg.MustInt("zero"))
+	assert.Equal(t, 0.5, cfg.MustFloat("ratio"))
+	assert.Equal(t, 1, cfg.MustInt("timeout"))
+	assert.Equal(t, 1.0, cfg.MustFloat("timeout"))
+	assert.Equal(t, float64(1234567890123456789), cfg.MustFloat("id"))
+	assert.Equal(t, 1000, cfg.MustInt("big"))
+	assert.Equal(t, ".5", cfg.MustString("half"))
+}
+
+func Test_ConfigParsePropertiesMalformed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "bad.properties")
+	if err := ioutil.WriteFile(path, []byte("db.host = a\ndb.port\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = config.ParsePropertiesFile(path)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "line 2")
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"errors"
+	"os"
+)
+
+// Sources reported by EffectiveConfig. Values read from a file are reported
+// as "file:" followed by its path, values fetched over HTTP as "url:" followed
+// by the URL and values taken from the environment as "env:" followed by the
+// variable name.
+const (
+	SourceInline  = "inline"
+	SourceDefault = "default"
+)
+
+// ValueInfo describes one leaf of EffectiveConfig: its final value, where it
+// came from and whether it is a global default rather than a configured value.
+type ValueInfo struct {
+	Value     interface{}
+	Source    string
+	IsDefault bool
+}
+
+// EffectiveConfig returns every leaf that lookups can see, keyed by path:
+// the leaves of the config itself plus the global defaults it does not
+// override. It is meant for debug endpoints and support output.
+func (c *ConfigImpl) EffectiveConfig() map[string]ValueInfo {
+	out := map[string]ValueInfo{}
+	c.Walk(func(path string, value interface{}) error {
+		out[path] = ValueInfo{Value: value, Source: c.sourceOf(path)}
+		return nil
+	})
+
+	globalMu.RLock()
+	defaults := copyValue(globalDefaults)
+	globalMu.RUnlock()
+	walk(defaults, "", defaultSeparator, func(path string, value interface{}) error {
+		parts := splitPath(path, defaultSeparator)
+		if _, err := fetchWith(c.tree(), parts, c.opts); errors.Is(err, ErrNotFound) {
+			out[c.join(parts...)] = ValueInfo{Value: value, Source: SourceDefault, IsDefault: true}
+		}
+		return nil
+	})
+	return out
+}
+
+func fileSource(path string) string {
+	return "file:" + path
+}
+
+// sourceOf returns the recorded source of the leaf at the path.
+func (c *ConfigImpl) sourceOf(path string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if src, ok := c.sources[path]; ok {
+		return src
+	}
+	return c.origin
+}
+
+// setOrigin attributes every leaf to src, forgetting per-leaf sources.
+func (c *ConfigImpl) setOrigin(src string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.origin = src
+	c.sources = nil
+}
+
+// noteSources records the sources of individual leaves.
+func (c *ConfigImpl) noteSources(sources map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.sources == nil {
+		c.sources = map[string]string{}
+	}
+	for path, src := range sources {
+		c.sources[path] = src
+	}
+}
+
+// leafOrigins maps every leaf of the config to its recorded source.
+func (c *ConfigImpl) leafOrigins() map[string]string {
+	out := map[string]string{}
+	c.Walk(func(path string, _ interface{}) error {
+		out[path] = c.sourceOf(path)
+		return nil
+	})
+	return out
+}
+
+// originsOf maps the leaves of any Config to their sources. Implementations
+// other than ConfigImpl do not record sources and map to "".
+func originsOf(cfg Config) map[string]string {
+	if impl, ok := cfg.(*ConfigImpl); ok {
+		return impl.leafOrigins()
+	}
+	return leafSources(cfg.Raw(), defaultSeparator, "")
+}
+
+// leafSources maps every leaf of node to src.
+func leafSources(node interface{}, sep, src string) map[string]string {
+	out := map[string]string{}
+	walk(node, "", sep, func(path string, _ interface{}) error {
+		out[path] = src
+		return nil
+	})
+	return out
+}
+
+// envSources maps the leaves of root that OverrideFromEnv took from the
+// environment to the variable that set them.
+func envSources(root map[string]interface{}, prefix, sep string) map[string]string {
+	out := map[string]string{}
+	walk(root, "", sep, func(path string, _ interface{}) error {
+		name := envName(prefix)
+		for _, seg := range splitPath(path, sep) {
+			name = joinEnv(name, envName(seg))
+		}
+		if _, ok := os.LookupEnv(name); ok {
+			out[path] = "env:" + name
+		}
+		return nil
+	})
+	return out
+}
@@ -0,0 +1,44 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigEffectiveConfig(t *testing.T) {
+	config.SetGlobalDefault("server.timeout", 30)
+	config.SetGlobalDefault("env", "dev")
+	defer config.ResetGlobalDefaults()
+
+	cfg, err := config.LoadFiles("resources/config/default.conf", "resources/config/production.conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer setenv(t, map[string]string{"APP_NAME": "Jane"})()
+	assert.NoError(t, cfg.OverrideFromEnv("APP"))
+
+	inline, err := config.ParseJSON(`{"clothes": {"size": "small"}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = cfg.Extend(inline)
+	assert.NoError(t, err)
+
+	effective := cfg.EffectiveConfig()
+	assert.Equal(t, config.ValueInfo{Value: "production", Source: "file:resources/config/production.conf"}, effective["env"])
+	assert.Equal(t, config.ValueInfo{Value: 26.0, Source: "file:resources/config/default.conf"}, effective["age"])
+	assert.Equal(t, config.ValueInfo{Value: "Jane", Source: "env:APP_NAME"}, effective["name"])
+	assert.Equal(t, config.ValueInfo{Value: "small", Source: config.SourceInline}, effective["clothes.size"])
+	assert.Equal(t, config.ValueInfo{Value: 30, Source: config.SourceDefault, IsDefault: true}, effective["server.timeout"])
+	_, ok := effective["clothes.pants.waist"]
+	assert.False(t, ok)
+	assert.Equal(t, len(cfg.Flatten())+1, len(effective))
+
+	file, err := config.ParseJSONFile("resources/config/local.conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "file:resources/config/local.conf", file.EffectiveConfig()["hobbies.0"].Source)
+}
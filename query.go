@@ -0,0 +1,77 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Query resolves a path that may contain "*" segments. A "*" maps the
+// rest of the path over every element of the list it is applied to and
+// collects the results, so "servers.*.host" returns the host of each server.
+// Wildcards compose with literal segments on either side and with each other,
+// e.g. "data.*.metrics.0". Elements where the remaining path does not resolve
+// are skipped; use QueryStrict to treat them as errors instead.
+func (c *ConfigImpl) Query(path string) ([]interface{}, error) {
+	return query(c.tree(), c.split(path), nil, c.separator(), false)
+}
+
+// QueryStrict is like Query but fails if the path does not resolve for any
+// element a wildcard is applied to.
+func (c *ConfigImpl) QueryStrict(path string) ([]interface{}, error) {
+	return query(c.tree(), c.split(path), nil, c.separator(), true)
+}
+
+func query(cfg interface{}, parts, prefix []string, sep string, strict bool) ([]interface{}, error) {
+	for pos, part := range parts {
+		if strings.TrimSpace(part) != "*" {
+			continue
+		}
+		head := append(append([]string{}, prefix...), parts[:pos]...)
+		wildcard := joinSegments(append(head, "*"), sep)
+		base, err := fetchParts(cfg, parts[:pos], sep)
+		if err != nil {
+			return nil, err
+		}
+		list, ok := base.([]interface{})
+		if !ok {
+			return nil, newPathError(ErrWrongType, "config: Unknown type at %q", wildcard)
+		}
+		out := []interface{}{}
+		for ix, elem := range list {
+			res, err := query(elem, parts[pos+1:], append(head, fmt.Sprint(ix)), sep, strict)
+			if err != nil {
+				if strict {
+					return nil, fmt.Errorf("config: Query element %d at %q: %w", ix, wildcard, err)
+				}
+				continue
+			}
+			out = append(out, res...)
+		}
+		return out, nil
+	}
+	v, err := fetchParts(cfg, parts, sep)
+	if err != nil {
+		return nil, err
+	}
+	return []interface{}{v}, nil
+}
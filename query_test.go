@@ -0,0 +1,41 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigQuery(t *testing.T) {
+	cfg, err := config.ParseJSON(`{
+		"servers": [{"host": "a"}, {"host": "b"}, {"port": 80}, {"host": "c"}],
+		"data": [
+			{"metrics": [1, 2]},
+			{"metrics": [3]},
+			{"metrics": []}
+		],
+		"groups": [{"members": [{"id": 1}, {"id": 2}]}, {"members": [{"id": 3}]}]
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hosts, err := cfg.Query("servers.*.host")
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"a", "b", "c"}, hosts)
+
+	firsts, err := cfg.Query("data.*.metrics.0")
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{1.0, 3.0}, firsts)
+
+	ids, err := cfg.Query("groups.*.members.*.id")
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{1.0, 2.0, 3.0}, ids)
+
+	_, err = cfg.QueryStrict("servers.*.host")
+	assert.Error(t, err)
+
+	_, err = cfg.Query("servers.0.*")
+	assert.Error(t, err)
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ResolveReferences replaces each "${path}" in string values with the value
+// at that path of the same config, so "${base_dir}/logs" follows base_dir.
+// The referenced value must be a string, number or bool; a referenced string
+// is resolved first, so references may be chained. "$${" writes a literal
+// "${". A reference to a missing path is ErrNotFound and references that
+// lead back to themselves are ErrInvalidValue; both name the reference and
+// the value containing it. Nothing is changed unless every reference
+// resolves.
+func (c *ConfigImpl) ResolveReferences() error {
+	defer c.beginWrite()()
+	orig := c.tree()
+	r := &refResolver{c: c, orig: orig, done: map[string]string{}, active: map[string]bool{}}
+	root := copyValue(orig).(map[string]interface{})
+	if err := r.node(root, ""); err != nil {
+		return err
+	}
+	return c.commit(root)
+}
+
+type refResolver struct {
+	c      *ConfigImpl
+	orig   map[string]interface{}
+	done   map[string]string
+	active map[string]bool
+	stack  []string
+}
+
+// node resolves the string values in node in place.
+func (r *refResolver) node(node interface{}, path string) error {
+	sep := r.c.separator()
+	resolve := func(v interface{}, path string) (interface{}, error) {
+		if s, ok := v.(string); ok {
+			return r.value(path, s)
+		}
+		return v, r.node(v, path)
+	}
+	switch x := node.(type) {
+	case map[string]interface{}:
+		for k, v := range x {
+			out, err := resolve(v, joinPath(path, k, sep))
+			if err != nil {
+				return err
+			}
+			x[k] = out
+		}
+	case []interface{}:
+		for i, v := range x {
+			out, err := resolve(v, joinPath(path, strconv.Itoa(i), sep))
+			if err != nil {
+				return err
+			}
+			x[i] = out
+		}
+	}
+	return nil
+}
+
+// value returns s, the string at path, with its references resolved.
+func (r *refResolver) value(path, s string) (string, error) {
+	if out, ok := r.done[path]; ok {
+		return out, nil
+	}
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+	if r.active[path] {
+		cycle := append(r.stack[indexOf(r.stack, path):], path)
+		return "", fetchError(ErrInvalidValue, path, path, "config: Reference cycle at %q: %s", path, strings.Join(cycle, " -> "))
+	}
+	r.active[path] = true
+	r.stack = append(r.stack, path)
+	defer func() {
+		delete(r.active, path)
+		r.stack = r.stack[:len(r.stack)-1]
+	}()
+
+	var b strings.Builder
+	for {
+		i := strings.Index(s, "${")
+		if i < 0 {
+			b.WriteString(s)
+			break
+		}
+		if i > 0 && s[i-1] == '$' {
+			b.WriteString(s[:i-1] + "${")
+			s = s[i+2:]
+			continue
+		}
+		end := strings.Index(s[i:], "}")
+		if end < 0 {
+			return "", fetchError(ErrInvalidValue, path, path, "config: Unterminated reference %q at %q", s[i:], path)
+		}
+		token, ref := s[i:i+end+1], strings.TrimSpace(s[i+2:i+end])
+		b.WriteString(s[:i])
+		s = s[i+end+1:]
+
+		parts := r.c.split(ref)
+		x, err := fetchWith(r.orig, parts, r.c.opts)
+		if err != nil {
+			return "", fetchError(ErrNotFound, path, path, "config: Undefined reference %s at %q", token, path)
+		}
+		switch v := x.(type) {
+		case string:
+			out, err := r.value(r.c.join(parts...), v)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(out)
+		default:
+			out, ok := coerceString(v)
+			if !ok {
+				return "", fetchError(ErrWrongType, path, path, "config: Reference %s at %q is not a string, number or bool", token, path)
+			}
+			b.WriteString(out)
+		}
+	}
+	r.done[path] = b.String()
+	return b.String(), nil
+}
+
+func indexOf(list []string, s string) int {
+	for i, e := range list {
+		if e == s {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,53 @@
+package config_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigResolveReferences(t *testing.T) {
+	cfg, err := config.ParseJSON(`{
+		"base_dir": "/srv/app",
+		"log_dir": "${base_dir}/logs",
+		"audit_log": "${log_dir}/audit.log",
+		"server": {"host": "api", "port": 8080, "url": "http://${server.host}:${server.port}"},
+		"paths": ["${log_dir}", "$${literal}"]
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NoError(t, cfg.ResolveReferences())
+	assert.Equal(t, "/srv/app/logs", cfg.MustString("log_dir"))
+	assert.Equal(t, "/srv/app/logs/audit.log", cfg.MustString("audit_log"))
+	assert.Equal(t, "http://api:8080", cfg.MustString("server.url"))
+	assert.Equal(t, []interface{}{"/srv/app/logs", "${literal}"}, cfg.MustList("paths"))
+}
+
+func Test_ConfigResolveReferencesErrors(t *testing.T) {
+	cfg, err := config.ParseJSON(`{"a": "${b}/x", "b": "${c}", "c": "${a}"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cfg.ResolveReferences()
+	assert.True(t, errors.Is(err, config.ErrInvalidValue))
+	assert.Contains(t, err.Error(), "config: Reference cycle at ")
+	assert.Equal(t, "${b}/x", cfg.MustString("a"))
+
+	cfg, err = config.ParseJSON(`{"log_dir": "${base_dir}/logs"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cfg.ResolveReferences()
+	assert.True(t, errors.Is(err, config.ErrNotFound))
+	assert.EqualError(t, err, `config: Undefined reference ${base_dir} at "log_dir"`)
+
+	cfg, err = config.ParseJSON(`{"db": {"host": "x"}, "url": "${db}", "bad": "${db.host"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Error(t, cfg.ResolveReferences())
+}
@@ -0,0 +1,194 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ReloadFile re-parses the JSON file at path and atomically replaces the
+// current data with it. Callbacks registered with OnChange run after the swap
+// for every watched path whose value changed. On error the current data is
+// left untouched.
+//
+// ReloadFile keeps the last known good data: if the file cannot be parsed or a
+// validator registered with ValidateReload rejects it, readers keep seeing the
+// previous values and the error is also passed to the OnReloadError callbacks.
+func (c *ConfigImpl) ReloadFile(path string) error {
+	defer c.beginWrite()()
+	cfg, err := parseJSONFile(path, c.opts)
+	if err == nil {
+		err = c.validateReload(cfg)
+	}
+	if err != nil {
+		err = fmt.Errorf("config: reloading %q: %w", path, err)
+		c.reloadFailed(err)
+		return err
+	}
+	c.replace(cfg.tree())
+	c.setOrigin(fileSource(path))
+	return nil
+}
+
+// ValidateReload registers a gate that every reloaded config must pass before
+// it replaces the current data.
+func (c *ConfigImpl) ValidateReload(fn func(Config) error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.validators = append(c.validators, fn)
+}
+
+// OnReloadError registers fn to be called with the error of every failed
+// reload. The previous data stays live when this happens.
+func (c *ConfigImpl) OnReloadError(fn func(error)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reloadErrs = append(c.reloadErrs, fn)
+}
+
+func (c *ConfigImpl) validateReload(cfg Config) error {
+	c.mu.RLock()
+	validators := append([]func(Config) error{}, c.validators...)
+	c.mu.RUnlock()
+	for _, fn := range validators {
+		if err := fn(cfg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *ConfigImpl) reloadFailed(err error) {
+	c.mu.RLock()
+	fns := append([]func(error){}, c.reloadErrs...)
+	c.mu.RUnlock()
+	for _, fn := range fns {
+		func() {
+			defer func() {
+				recover()
+			}()
+			fn(err)
+		}()
+	}
+}
+
+// OnChange registers fn to be called after a reload changes the value at the
+// dotted path. Values are compared with reflect.DeepEqual; old or new is nil
+// when the path is absent on that side. Callbacks for the same path run in
+// registration order, and a panicking callback does not stop the others.
+func (c *ConfigImpl) OnChange(path string, fn func(old, new interface{})) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.watchers == nil {
+		c.watchers = map[string][]func(old, new interface{}){}
+	}
+	c.watchers[path] = append(c.watchers[path], fn)
+}
+
+// replace swaps in root and queues notifications for the watchers whose value
+// changed. The caller must hold the writer lock taken by beginWrite.
+func (c *ConfigImpl) replace(root map[string]interface{}) {
+	c.tree() // watchers compare whole trees, so decode any lazy sections
+	c.mu.Lock()
+	old := c.root
+	c.root, c.pending = root, nil
+	c.invalidateMemo(old)
+	watchers := make(map[string][]func(old, new interface{}), len(c.watchers))
+	for path, fns := range c.watchers {
+		watchers[path] = append([]func(old, new interface{}){}, fns...)
+	}
+	c.mu.Unlock()
+
+	for path, fns := range watchers {
+		ov, oerr := fetchParts(old, c.split(path), c.separator())
+		nv, nerr := fetchParts(root, c.split(path), c.separator())
+		if (oerr == nil) == (nerr == nil) && reflect.DeepEqual(ov, nv) {
+			continue
+		}
+		for _, fn := range fns {
+			fn := fn
+			c.notices = append(c.notices, func() { notify(fn, ov, nv) })
+		}
+	}
+}
+
+// beginWrite takes the writer lock that serializes mutations, so that each
+// one reads the root, checks seals and swaps in its result without another
+// writer in between. The returned function releases the lock and then runs
+// the OnChange callbacks queued by replace, so callbacks may change the
+// config themselves. Mutations use it as
+//
+//	defer c.beginWrite()()
+func (c *ConfigImpl) beginWrite() func() {
+	c.writeMu.Lock()
+	return func() {
+		notices := c.notices
+		c.notices = nil
+		c.writeMu.Unlock()
+		for _, fn := range notices {
+			fn()
+		}
+	}
+}
+
+func notify(fn func(old, new interface{}), old, new interface{}) {
+	defer func() {
+		recover()
+	}()
+	fn(old, new)
+}
+
+// WatchString calls fn with the string at path now and again after every
+// reload that changes it. Calls are skipped while the value is missing or not
+// a string.
+func (c *ConfigImpl) WatchString(path string, fn func(string)) {
+	c.watch(path, func() {
+		if s, err := c.String(path); err == nil {
+			fn(s)
+		}
+	})
+}
+
+// WatchInt is the int counterpart of WatchString.
+func (c *ConfigImpl) WatchInt(path string, fn func(int)) {
+	c.watch(path, func() {
+		if i, err := c.Int(path); err == nil {
+			fn(i)
+		}
+	})
+}
+
+// WatchBool is the bool counterpart of WatchString.
+func (c *ConfigImpl) WatchBool(path string, fn func(bool)) {
+	c.watch(path, func() {
+		if b, err := c.Bool(path); err == nil {
+			fn(b)
+		}
+	})
+}
+
+func (c *ConfigImpl) watch(path string, read func()) {
+	read()
+	c.OnChange(path, func(old, new interface{}) {
+		read()
+	})
+}
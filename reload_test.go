@@ -0,0 +1,129 @@
+package config_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTempConfig(t *testing.T, dir, data string) string {
+	path := filepath.Join(dir, "app.conf")
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func Test_ConfigOnChange(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := writeTempConfig(t, dir, `{"debug": true, "env": "default", "db": {"port": 5432}}`)
+	cfg, err := config.ParseJSONFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var calls []string
+	cfg.OnChange("debug", func(old, new interface{}) {
+		assert.Equal(t, true, old)
+		assert.Equal(t, false, new)
+		calls = append(calls, "first")
+	})
+	cfg.OnChange("debug", func(old, new interface{}) {
+		panic("boom")
+	})
+	cfg.OnChange("debug", func(old, new interface{}) {
+		calls = append(calls, "third")
+	})
+	cfg.OnChange("env", func(old, new interface{}) {
+		calls = append(calls, "env")
+	})
+	cfg.OnChange("db.port", func(old, new interface{}) {
+		assert.Equal(t, 5432.0, old)
+		assert.Nil(t, new)
+		calls = append(calls, "port")
+	})
+
+	writeTempConfig(t, dir, `{"debug": false, "env": "default", "db": {}}`)
+	assert.NoError(t, cfg.ReloadFile(path))
+	assert.ElementsMatch(t, []string{"first", "third", "port"}, calls)
+	assert.Equal(t, false, cfg.MustBool("debug", true))
+}
+
+func Test_ConfigReloadKeepsLastKnownGood(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := writeTempConfig(t, dir, `{"env": "stagging", "workers": 4}`)
+	cfg, err := config.ParseJSONFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var reloadErrs []error
+	cfg.OnReloadError(func(err error) {
+		reloadErrs = append(reloadErrs, err)
+	})
+	cfg.ValidateReload(func(next config.Config) error {
+		if next.MustInt("workers") < 1 {
+			return errors.New("workers must be positive")
+		}
+		return nil
+	})
+
+	writeTempConfig(t, dir, `{"env": "production", "workers": `)
+	assert.Error(t, cfg.ReloadFile(path))
+	assert.Equal(t, "stagging", cfg.MustString("env"))
+
+	writeTempConfig(t, dir, `{"env": "production", "workers": 0}`)
+	assert.Error(t, cfg.ReloadFile(path))
+	assert.Equal(t, "stagging", cfg.MustString("env"))
+	assert.Equal(t, 4, cfg.MustInt("workers"))
+	assert.Len(t, reloadErrs, 2)
+
+	writeTempConfig(t, dir, `{"env": "production", "workers": 8}`)
+	assert.NoError(t, cfg.ReloadFile(path))
+	assert.Equal(t, "production", cfg.MustString("env"))
+}
+
+func Test_ConfigWatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := writeTempConfig(t, dir, `{"log": {"level": "info"}, "workers": 2, "debug": false}`)
+	cfg, err := config.ParseJSONFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var levels []string
+	var workers []int
+	var debug []bool
+	cfg.WatchString("log.level", func(s string) { levels = append(levels, s) })
+	cfg.WatchInt("workers", func(i int) { workers = append(workers, i) })
+	cfg.WatchBool("debug", func(b bool) { debug = append(debug, b) })
+	assert.Equal(t, []string{"info"}, levels)
+	assert.Equal(t, []int{2}, workers)
+	assert.Equal(t, []bool{false}, debug)
+
+	writeTempConfig(t, dir, `{"log": {"level": "debug"}, "workers": 2, "debug": true}`)
+	assert.NoError(t, cfg.ReloadFile(path))
+	assert.Equal(t, []string{"info", "debug"}, levels)
+	assert.Equal(t, []int{2}, workers)
+	assert.Equal(t, []bool{false, true}, debug)
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+)
+
+// A document whose top level is a JSON array, such as a list of rules, is
+// stored as a root map keyed by element index: the elements are reached with
+// index paths like "0" and "1.name" and the typed getters read elements
+// exactly as they read top-level keys. The root itself stays a map: Keys("")
+// lists the indices, in string order, List("") fails with ErrWrongType, and
+// Raw, Render and MarshalJSON write an object keyed by index.
+
+// isJSONArray reports whether data, after leading white space, starts a
+// JSON array.
+func isJSONArray(data []byte) bool {
+	data = bytes.TrimLeft(data, " \t\r\n")
+	return len(data) > 0 && data[0] == '['
+}
+
+// decodeArrayRoot decodes the top-level array read by dec one element at a
+// time, so a large list never exists as a whole next to the root built from
+// it.
+func decodeArrayRoot(dec *json.Decoder) (map[string]interface{}, error) {
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	root := map[string]interface{}{}
+	for i := 0; dec.More(); i++ {
+		var elem interface{}
+		if err := dec.Decode(&elem); err != nil {
+			return nil, err
+		}
+		root[strconv.Itoa(i)] = elem
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("config: unexpected data after JSON document")
+	}
+	return root, nil
+}
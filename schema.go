@@ -0,0 +1,84 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SchemaValidator checks a JSON document against a JSON Schema. The package
+// ships no implementation so that it does not depend on a schema library;
+// wrap the one you use and install it with WithSchemaValidator.
+type SchemaValidator interface {
+	// Validate returns one violation per problem found, or an error when
+	// the schema itself cannot be used.
+	Validate(schema, document []byte) ([]SchemaViolation, error)
+}
+
+// SchemaViolation is a single failed schema constraint. Path locates the
+// offending value in the validator's own notation, such as a JSON pointer.
+type SchemaViolation struct {
+	Path    string
+	Message string
+}
+
+// SchemaValidatorFunc adapts a function to SchemaValidator.
+type SchemaValidatorFunc func(schema, document []byte) ([]SchemaViolation, error)
+
+// Validate calls f(schema, document).
+func (f SchemaValidatorFunc) Validate(schema, document []byte) ([]SchemaViolation, error) {
+	return f(schema, document)
+}
+
+// WithSchemaValidator sets the validator used by ValidateJSONSchema.
+func WithSchemaValidator(v SchemaValidator) Option {
+	return func(o *options) {
+		o.schema = v
+	}
+}
+
+// ValidateJSONSchema encodes the config as JSON and checks it against the
+// JSON Schema with the validator set by WithSchemaValidator. All violations
+// are returned in one ErrInvalidValue error, one "path: message" per line.
+func (c *ConfigImpl) ValidateJSONSchema(schema []byte) error {
+	if c.opts.schema == nil {
+		return errors.New("config: ValidateJSONSchema requires a validator set with WithSchemaValidator")
+	}
+	doc, err := json.Marshal(c.tree())
+	if err != nil {
+		return fmt.Errorf("config: encoding config: %w", err)
+	}
+	violations, err := c.opts.schema.Validate(schema, doc)
+	if err != nil {
+		return fmt.Errorf("config: JSON schema: %w", err)
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	problems := make([]string, len(violations))
+	for i, v := range violations {
+		problems[i] = v.Path + ": " + v.Message
+	}
+	return newPathError(ErrInvalidValue, "config: JSON schema validation failed:\n  %s", strings.Join(problems, "\n  "))
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"reflect"
+)
+
+// Seal protects the subtree at path from later layers: Extend, Merge,
+// ApplyPatch, OverrideFromEnv, Set, Delete and Transaction fail with
+// ErrSealed instead of changing anything inside it.
+// Sibling subtrees stay mutable. ReloadFile replaces the whole config and is
+// not subject to seals.
+func (c *ConfigImpl) Seal(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sealed = append(c.sealed, path)
+}
+
+// checkSealed reports an error if next differs from the current root inside
+// any sealed subtree.
+func (c *ConfigImpl) checkSealed(next map[string]interface{}) error {
+	root := c.tree()
+	c.mu.RLock()
+	sealed := append([]string{}, c.sealed...)
+	c.mu.RUnlock()
+	for _, path := range sealed {
+		parts := c.split(path)
+		ov, oerr := fetchParts(root, parts, c.separator())
+		nv, nerr := fetchParts(next, parts, c.separator())
+		if (oerr == nil) != (nerr == nil) || !reflect.DeepEqual(ov, nv) {
+			return newPathError(ErrSealed, "config: Sealed path %q cannot be modified", path)
+		}
+	}
+	return nil
+}
+
+// commit replaces the root with next unless that would modify a sealed
+// subtree. Like replace, it must be called under the writer lock, with next
+// computed from the root read under that lock.
+func (c *ConfigImpl) commit(next map[string]interface{}) error {
+	if err := c.checkSealed(next); err != nil {
+		return err
+	}
+	c.replace(next)
+	return nil
+}
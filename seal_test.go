@@ -0,0 +1,43 @@
+package config_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigSeal(t *testing.T) {
+	cfg, err := config.ParseJSON(`{
+		"security": {"tls": {"min_version": "1.2"}, "admins": ["root"]},
+		"server": {"port": 8080}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Seal("security.tls")
+
+	overlay, _ := config.ParseJSON(`{"security": {"tls": {"min_version": "1.0"}}}`)
+	_, err = cfg.Extend(overlay)
+	assert.True(t, errors.Is(err, config.ErrSealed))
+	assert.Equal(t, "1.2", cfg.MustString("security.tls.min_version"))
+
+	err = cfg.ApplyPatch(map[string]interface{}{"security": map[string]interface{}{"tls": nil}})
+	assert.True(t, errors.Is(err, config.ErrSealed))
+	assert.Equal(t, "1.2", cfg.MustString("security.tls.min_version"))
+
+	assert.NoError(t, cfg.ApplyPatch(map[string]interface{}{
+		"security": map[string]interface{}{"admins": []interface{}{"root", "ops"}},
+	}))
+	assert.Equal(t, []interface{}{"root", "ops"}, cfg.MustList("security.admins"))
+
+	overlay, _ = config.ParseJSON(`{"server": {"port": 9090}}`)
+	_, err = cfg.Extend(overlay)
+	assert.NoError(t, err)
+	assert.Equal(t, 9090, cfg.MustInt("server.port"))
+
+	defer setenv(t, map[string]string{"APP_SECURITY_TLS_MIN_VERSION": "1.0"})()
+	assert.True(t, errors.Is(cfg.OverrideFromEnv("APP"), config.ErrSealed))
+	assert.Equal(t, "1.2", cfg.MustString("security.tls.min_version"))
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// Source supplies raw config data for LoadFrom together with its format:
+// "json", "jsonc", "ini", "properties" or "env". Implement it to load config
+// from backends such as etcd or Consul without this package depending on
+// them.
+type Source interface {
+	Load() (data []byte, format string, err error)
+}
+
+// LoadFrom loads data from src and parses it with the parser for the format
+// it reports.
+func LoadFrom(src Source, opts ...Option) (Config, error) {
+	data, format, err := src.Load()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := parseFormat(data, format, newOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	if named, ok := src.(namedSource); ok {
+		cfg.origin = named.sourceName()
+	}
+	return cfg, nil
+}
+
+// namedSource is implemented by the sources of this package to label the
+// values they load for EffectiveConfig.
+type namedSource interface {
+	sourceName() string
+}
+
+func parseFormat(data []byte, format string, o options) (*ConfigImpl, error) {
+	switch strings.ToLower(format) {
+	case "json":
+		return parseJSON(data, o)
+	case "jsonc":
+		stripped, err := stripJSONC(data)
+		if err != nil {
+			return nil, err
+		}
+		return parseJSON(stripped, o)
+	case "ini":
+		return parseINI(data, o)
+	case "properties":
+		return parseKeyValues(data, false, o)
+	case "env":
+		return parseKeyValues(data, true, o)
+	}
+	return nil, fmt.Errorf("config: Unknown format %q", format)
+}
+
+// FileSource loads a file. When Format is empty it is derived from the
+// extension: .jsonc, .ini, .properties and .env select their parsers and
+// anything else is read as JSON.
+type FileSource struct {
+	Path   string
+	Format string
+}
+
+// Load implements Source.
+func (s FileSource) Load() ([]byte, string, error) {
+	data, err := ioutil.ReadFile(s.Path)
+	if err != nil {
+		return nil, "", err
+	}
+	format := s.Format
+	if format == "" {
+		format = formatOf(s.Path)
+	}
+	return data, format, nil
+}
+
+func (s FileSource) sourceName() string {
+	return fileSource(s.Path)
+}
+
+func formatOf(path string) string {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".jsonc", ".ini", ".properties", ".env":
+		return ext[1:]
+	}
+	return "json"
+}
+
+// HTTPSource fetches a URL with the checks of ParseJSONURL. Client defaults
+// to one with a 30 second timeout and Format to "json".
+type HTTPSource struct {
+	URL    string
+	Client *http.Client
+	Format string
+}
+
+// Load implements Source.
+func (s HTTPSource) Load() ([]byte, string, error) {
+	data, err := fetchURL(context.Background(), s.URL, options{httpClient: s.Client})
+	if err != nil {
+		return nil, "", err
+	}
+	format := s.Format
+	if format == "" {
+		format = "json"
+	}
+	return data, format, nil
+}
+
+func (s HTTPSource) sourceName() string {
+	return "url:" + s.URL
+}
+
+// ReaderSource reads all of Reader as data in the given Format.
+type ReaderSource struct {
+	Reader io.Reader
+	Format string
+}
+
+// Load implements Source.
+func (s ReaderSource) Load() ([]byte, string, error) {
+	data, err := ioutil.ReadAll(s.Reader)
+	return data, s.Format, err
+}
+
+func (s ReaderSource) sourceName() string {
+	return SourceInline
+}
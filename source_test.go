@@ -0,0 +1,60 @@
+package config_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// staticSource is a user-defined Source.
+type staticSource struct {
+	data, format string
+	err          error
+}
+
+func (s staticSource) Load() ([]byte, string, error) {
+	return []byte(s.data), s.format, s.err
+}
+
+func Test_ConfigLoadFrom(t *testing.T) {
+	cfg, err := config.LoadFrom(config.FileSource{Path: "resources/config/default.conf"})
+	assert.NoError(t, err)
+	assert.Equal(t, "John", cfg.MustString("name"))
+
+	cfg, err = config.LoadFrom(config.FileSource{Path: "resources/config/app.ini"})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, cfg.Flatten())
+
+	cfg, err = config.LoadFrom(config.FileSource{Path: "resources/config/commented.jsonc"})
+	assert.NoError(t, err)
+	assert.Equal(t, "0.0.0.0:8080", cfg.MustString("listen"))
+
+	cfg, err = config.LoadFrom(config.ReaderSource{Reader: strings.NewReader("db.port=5432"), Format: "properties"})
+	assert.NoError(t, err)
+	assert.Equal(t, 5432, cfg.MustInt("db.port"))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("[server]\nport = 9090\n"))
+	}))
+	defer srv.Close()
+	cfg, err = config.LoadFrom(config.HTTPSource{URL: srv.URL, Format: "ini"})
+	assert.NoError(t, err)
+	assert.Equal(t, 9090, cfg.MustInt("server.port"))
+	assert.Equal(t, "url:"+srv.URL, cfg.EffectiveConfig()["server.port"].Source)
+
+	cfg, err = config.LoadFrom(staticSource{data: `{"from": "custom"}`, format: "JSON"})
+	assert.NoError(t, err)
+	assert.Equal(t, "custom", cfg.MustString("from"))
+
+	_, err = config.LoadFrom(staticSource{data: `a: b`, format: "yaml"})
+	assert.EqualError(t, err, `config: Unknown format "yaml"`)
+
+	failure := errors.New("backend down")
+	_, err = config.LoadFrom(staticSource{err: failure})
+	assert.True(t, errors.Is(err, failure))
+}
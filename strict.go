@@ -0,0 +1,122 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"fmt"
+)
+
+// The strict Must getters behave like their lenient counterparts while a
+// value or a default is available, but panic instead of returning a zero
+// value when the path is missing or holds the wrong type and no default was
+// given. The panic message names the path and the expected type.
+
+func strictFailure(path, typ string, err error) string {
+	return fmt.Sprintf("config: expected %s at %q: %v", typ, path, err)
+}
+
+func (c *ConfigImpl) MustStringStrict(path string, defaults ...string) string {
+	s, err := c.String(path)
+	if err == nil && !c.useDefault(path, len(defaults)) {
+		return s
+	}
+	for _, def := range defaults {
+		return def
+	}
+	panic(strictFailure(path, "string", err))
+}
+
+func (c *ConfigImpl) MustBoolStrict(path string, defaults ...bool) bool {
+	b, err := c.Bool(path)
+	if err == nil && !c.useDefault(path, len(defaults)) {
+		return b
+	}
+	for _, def := range defaults {
+		return def
+	}
+	panic(strictFailure(path, "bool", err))
+}
+
+func (c *ConfigImpl) MustIntStrict(path string, defaults ...int) int {
+	i, err := c.Int(path)
+	if err == nil && !c.useDefault(path, len(defaults)) {
+		return i
+	}
+	for _, def := range defaults {
+		return def
+	}
+	panic(strictFailure(path, "int", err))
+}
+
+func (c *ConfigImpl) MustInt64Strict(path string, defaults ...int64) int64 {
+	i, err := c.Int64(path)
+	if err == nil && !c.useDefault(path, len(defaults)) {
+		return i
+	}
+	for _, def := range defaults {
+		return def
+	}
+	panic(strictFailure(path, "int64", err))
+}
+
+func (c *ConfigImpl) MustUint64Strict(path string, defaults ...uint64) uint64 {
+	u, err := c.Uint64(path)
+	if err == nil && !c.useDefault(path, len(defaults)) {
+		return u
+	}
+	for _, def := range defaults {
+		return def
+	}
+	panic(strictFailure(path, "uint64", err))
+}
+
+func (c *ConfigImpl) MustFloatStrict(path string, defaults ...float64) float64 {
+	f, err := c.Float(path)
+	if err == nil && !c.useDefault(path, len(defaults)) {
+		return f
+	}
+	for _, def := range defaults {
+		return def
+	}
+	panic(strictFailure(path, "float", err))
+}
+
+func (c *ConfigImpl) MustMapStrict(path string, defaults ...map[string]interface{}) map[string]interface{} {
+	m, err := c.Map(path)
+	if err == nil && !c.useDefault(path, len(defaults)) {
+		return m
+	}
+	for _, def := range defaults {
+		return def
+	}
+	panic(strictFailure(path, "map", err))
+}
+
+func (c *ConfigImpl) MustListStrict(path string, defaults ...[]interface{}) []interface{} {
+	l, err := c.List(path)
+	if err == nil && !c.useDefault(path, len(defaults)) {
+		return l
+	}
+	for _, def := range defaults {
+		return def
+	}
+	panic(strictFailure(path, "list", err))
+}
@@ -0,0 +1,35 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigMustStrict(t *testing.T) {
+	cfg, err := config.ParseJSON(`{"port": 8080, "name": "api", "ratio": 0.5, "tags": ["a"], "db": {}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 8080, cfg.MustIntStrict("port"))
+	assert.Equal(t, "api", cfg.MustStringStrict("name"))
+	assert.Equal(t, 0.5, cfg.MustFloatStrict("ratio"))
+	assert.Equal(t, []interface{}{"a"}, cfg.MustListStrict("tags"))
+	assert.Equal(t, map[string]interface{}{}, cfg.MustMapStrict("db"))
+	assert.Equal(t, 9000, cfg.MustIntStrict("missing", 9000))
+	assert.True(t, cfg.MustBoolStrict("missing", true))
+
+	assert.PanicsWithValue(t, `config: expected int at "missing": config: Unknown path at "missing"`, func() {
+		cfg.MustIntStrict("missing")
+	})
+	assert.PanicsWithValue(t, `config: expected int at "name": config: Unknown type at "name"`, func() {
+		cfg.MustIntStrict("name")
+	})
+	assert.Panics(t, func() { cfg.MustBoolStrict("port") })
+	assert.Panics(t, func() { cfg.MustUint64Strict("name") })
+	assert.Panics(t, func() { cfg.MustInt64Strict("ratio") })
+	assert.Panics(t, func() { cfg.MustStringStrict("port") })
+	assert.Equal(t, 0, cfg.MustInt("missing"))
+}
@@ -0,0 +1,223 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// EachConfig calls fn for every element of the list of maps at the path,
+// wrapping each element as a Config that shares the parent's options. It stops
+// at and returns the first error from fn, and fails before calling fn if an
+// element is not a map.
+func (c *ConfigImpl) EachConfig(path string, fn func(index int, c Config) error) error {
+	list, err := c.mapList(path)
+	if err != nil {
+		return err
+	}
+	for ix, m := range list {
+		if err := fn(ix, c.child(m)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SubList returns the list of objects at the path with each element wrapped
+// as a Config sharing the receiver's options, so the typed getters work on
+// every element. An element that is not a map is ErrWrongType naming its
+// index.
+func (c *ConfigImpl) SubList(path string) ([]Config, error) {
+	list, err := c.mapList(path)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Config, len(list))
+	for ix, m := range list {
+		out[ix] = c.child(m)
+	}
+	return out, nil
+}
+
+// MustSubList is SubList returning nil on error, so a missing list ranges
+// over nothing.
+func (c *ConfigImpl) MustSubList(path string) []Config {
+	out, err := c.SubList(path)
+	if err != nil {
+		return nil
+	}
+	return out
+}
+
+// SubListResolved returns the list of maps at the path as Configs after
+// resolving inheritance between elements. An element whose extendsKey names
+// another element (by its nameKey) is deep-merged over a copy of that element,
+// its own values winning. Chains are followed; unknown names, duplicate names
+// and cycles are errors. The underlying config is not modified.
+func (c *ConfigImpl) SubListResolved(path, extendsKey, nameKey string) ([]Config, error) {
+	list, err := c.mapList(path)
+	if err != nil {
+		return nil, err
+	}
+	byName := map[string]int{}
+	for ix, m := range list {
+		if name, ok := m[nameKey].(string); ok {
+			if _, dup := byName[name]; dup {
+				return nil, fmt.Errorf("config: Duplicate %s %q at %q", nameKey, name, path)
+			}
+			byName[name] = ix
+		}
+	}
+
+	resolved := make([]map[string]interface{}, len(list))
+	var resolve func(ix int, seen map[int]bool) (map[string]interface{}, error)
+	resolve = func(ix int, seen map[int]bool) (map[string]interface{}, error) {
+		if resolved[ix] != nil {
+			return resolved[ix], nil
+		}
+		if seen[ix] {
+			return nil, fmt.Errorf("config: Cyclic %s at %q", extendsKey, c.join(append(c.split(path), strconv.Itoa(ix))...))
+		}
+		seen[ix] = true
+		out := map[string]interface{}{}
+		if base, ok := list[ix][extendsKey].(string); ok {
+			bix, found := byName[base]
+			if !found {
+				return nil, fmt.Errorf("config: Unknown %s %q at %q", extendsKey, base, c.join(append(c.split(path), strconv.Itoa(ix))...))
+			}
+			parent, err := resolve(bix, seen)
+			if err != nil {
+				return nil, err
+			}
+			mergeDeep(out, parent)
+		}
+		mergeDeep(out, list[ix])
+		resolved[ix] = out
+		return out, nil
+	}
+
+	out := make([]Config, len(list))
+	for ix := range list {
+		m, err := resolve(ix, map[int]bool{})
+		if err != nil {
+			return nil, err
+		}
+		out[ix] = c.child(m)
+	}
+	return out, nil
+}
+
+// NormalizeToList returns the list at the path, wrapping a single map in a
+// one-element list. It smooths over documents that hold either one object or a
+// list of them at the same key. Other scalar values are ErrWrongType.
+func (c *ConfigImpl) NormalizeToList(path string) ([]interface{}, error) {
+	x, err := c.Get(path)
+	if err != nil {
+		return nil, err
+	}
+	switch v := x.(type) {
+	case []interface{}:
+		return v, nil
+	case map[string]interface{}:
+		return []interface{}{v}, nil
+	}
+	return nil, wrongType(x, path)
+}
+
+// FilterListByEnv returns the elements of the list at the path whose "env"
+// field equals env. Elements without an "env" field apply to every
+// environment and are always kept; so are non-map elements. An "env" field
+// may also hold a list of environment names.
+func (c *ConfigImpl) FilterListByEnv(path, env string) ([]interface{}, error) {
+	list, err := c.List(path)
+	if err != nil {
+		return nil, err
+	}
+	out := []interface{}{}
+	for _, e := range list {
+		m, ok := e.(map[string]interface{})
+		if !ok {
+			out = append(out, e)
+			continue
+		}
+		tag, tagged := m["env"]
+		if !tagged || matchesEnv(tag, env) {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func matchesEnv(tag interface{}, env string) bool {
+	switch v := tag.(type) {
+	case string:
+		return v == env
+	case []interface{}:
+		for _, e := range v {
+			if s, ok := e.(string); ok && s == env {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MapList returns the list of objects at the path, such as a list of servers,
+// with each element as a map. An element that is not a map is ErrWrongType
+// naming its index. The maps are the config's own data and must not be
+// modified; EachConfig wraps each element as a Config instead.
+func (c *ConfigImpl) MapList(path string) ([]map[string]interface{}, error) {
+	return c.mapList(path)
+}
+
+func (c *ConfigImpl) MustMapList(path string, defaults ...[]map[string]interface{}) []map[string]interface{} {
+	l, err := c.MapList(path)
+	if err == nil && !c.useDefault(path, len(defaults)) {
+		return l
+	}
+	for _, def := range defaults {
+		return def
+	}
+	return []map[string]interface{}{}
+}
+
+// mapList returns the list at the path, requiring every element to be a map.
+func (c *ConfigImpl) mapList(path string) ([]map[string]interface{}, error) {
+	list, err := c.List(path)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]map[string]interface{}, len(list))
+	for ix, e := range list {
+		m, ok := e.(map[string]interface{})
+		if !ok {
+			return nil, newPathError(ErrWrongType, "config: Unknown type at %q", c.join(append(c.split(path), strconv.Itoa(ix))...))
+		}
+		out[ix] = m
+	}
+	return out, nil
+}
+
+// child wraps a subtree as a Config sharing the receiver's options.
+func (c *ConfigImpl) child(root map[string]interface{}) *ConfigImpl {
+	return &ConfigImpl{opts: c.opts, root: root, origin: c.origin}
+}
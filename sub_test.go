@@ -0,0 +1,178 @@
+package config_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigEachConfig(t *testing.T) {
+	cfg, err := config.ParseJSON(`{
+		"listeners": [
+			{"name": "http", "port": 80},
+			{"name": "https", "port": 443, "tls": {"cert": "site.pem"}}
+		],
+		"mixed": [{"name": "a"}, "b"]
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var seen []string
+	err = cfg.EachConfig("listeners", func(ix int, l config.Config) error {
+		seen = append(seen, l.MustString("name"))
+		if ix == 1 {
+			assert.Equal(t, 443, l.MustInt("port"))
+			assert.Equal(t, "site.pem", l.MustString("tls.cert"))
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"http", "https"}, seen)
+
+	stop := errors.New("stop")
+	calls := 0
+	err = cfg.EachConfig("listeners", func(ix int, l config.Config) error {
+		calls++
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 1, calls)
+
+	err = cfg.EachConfig("mixed", func(int, config.Config) error { return nil })
+	assert.True(t, errors.Is(err, config.ErrWrongType))
+	assert.Contains(t, err.Error(), "mixed.1")
+}
+
+func Test_ConfigSubListResolved(t *testing.T) {
+	cfg, err := config.ParseJSON(`{
+		"pools": [
+			{"name": "base", "size": 4, "timeouts": {"read": 5, "write": 10}},
+			{"name": "child", "extends": "base", "timeouts": {"write": 20}},
+			{"name": "grandchild", "extends": "child", "size": 8}
+		],
+		"broken": [
+			{"name": "a", "extends": "b"},
+			{"name": "b", "extends": "a"}
+		]
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pools, err := cfg.SubListResolved("pools", "extends", "name")
+	assert.NoError(t, err)
+	assert.Len(t, pools, 3)
+	assert.Equal(t, "child", pools[1].MustString("name"))
+	assert.Equal(t, 4, pools[1].MustInt("size"))
+	assert.Equal(t, 5, pools[1].MustInt("timeouts.read"))
+	assert.Equal(t, 20, pools[1].MustInt("timeouts.write"))
+	assert.Equal(t, 8, pools[2].MustInt("size"))
+	assert.Equal(t, 20, pools[2].MustInt("timeouts.write"))
+
+	_, err = cfg.Int("pools.1.size")
+	assert.Error(t, err)
+
+	_, err = cfg.SubListResolved("broken", "extends", "name")
+	assert.Error(t, err)
+}
+
+func Test_ConfigNormalizeToList(t *testing.T) {
+	single, err := config.ParseJSON(`{"backend": {"host": "a"}}`)
+	assert.NoError(t, err)
+	list, err := single.NormalizeToList("backend")
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{map[string]interface{}{"host": "a"}}, list)
+
+	many, err := config.ParseJSON(`{"backend": [{"host": "a"}, {"host": "b"}]}`)
+	assert.NoError(t, err)
+	list, err = many.NormalizeToList("backend")
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+
+	scalar, err := config.ParseJSON(`{"backend": "a"}`)
+	assert.NoError(t, err)
+	_, err = scalar.NormalizeToList("backend")
+	assert.True(t, errors.Is(err, config.ErrWrongType))
+}
+
+func Test_ConfigFilterListByEnv(t *testing.T) {
+	cfg, err := config.ParseJSON(`{"rollouts": [
+		{"name": "search", "env": "production"},
+		{"name": "beta", "env": "staging"},
+		{"name": "audit"},
+		{"name": "cache", "env": ["staging", "production"]}
+	]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	list, err := cfg.FilterListByEnv("rollouts", "production")
+	assert.NoError(t, err)
+	for _, e := range list {
+		names = append(names, e.(map[string]interface{})["name"].(string))
+	}
+	assert.Equal(t, []string{"search", "audit", "cache"}, names)
+
+	list, err = cfg.FilterListByEnv("rollouts", "dev")
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+
+	_, err = cfg.FilterListByEnv("missing", "dev")
+	assert.True(t, errors.Is(err, config.ErrNotFound))
+}
+
+func Test_ConfigMapList(t *testing.T) {
+	cfg, err := config.ParseJSON(`{
+		"pipelines": [
+			{"name": "metrics", "exporters": ["prometheus"]},
+			{"name": "traces", "exporters": ["otlp", "jaeger"]}
+		],
+		"mixed": [{"name": "ok"}, "oops"]
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pipelines, err := cfg.MapList("pipelines")
+	assert.NoError(t, err)
+	if assert.Len(t, pipelines, 2) {
+		assert.Equal(t, "metrics", pipelines[0]["name"])
+		assert.Equal(t, []interface{}{"otlp", "jaeger"}, pipelines[1]["exporters"])
+	}
+
+	_, err = cfg.MapList("mixed")
+	assert.True(t, errors.Is(err, config.ErrWrongType))
+	assert.EqualError(t, err, `config: Unknown type at "mixed.1"`)
+	assert.Equal(t, []map[string]interface{}{}, cfg.MustMapList("mixed"))
+	assert.Len(t, cfg.MustMapList("missing", []map[string]interface{}{{}}), 1)
+}
+
+func Test_ConfigSubList(t *testing.T) {
+	cfg, err := config.ParseJSON(`{
+		"servers": [{"host": "a", "port": 80}, {"host": "b", "port": 81, "tls": {"cert": "b.pem"}}],
+		"broken": [{"host": "a"}, 42]
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var hosts []string
+	for _, s := range cfg.MustSubList("servers") {
+		hosts = append(hosts, s.MustString("host"))
+	}
+	assert.Equal(t, []string{"a", "b"}, hosts)
+
+	servers, err := cfg.SubList("servers")
+	assert.NoError(t, err)
+	assert.Equal(t, 81, servers[1].MustInt("port"))
+	assert.Equal(t, "b.pem", servers[1].MustString("tls.cert"))
+
+	_, err = cfg.SubList("broken")
+	assert.EqualError(t, err, `config: Unknown type at "broken.1"`)
+	assert.Nil(t, cfg.MustSubList("broken"))
+	assert.Empty(t, cfg.MustSubList("missing"))
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// maxTemplatePasses bounds RenderTemplates so that templates referring to
+// each other in a cycle fail instead of rendering forever.
+const maxTemplatePasses = 10
+
+// RenderTemplates treats every string value containing "{{" as a
+// text/template and replaces it with its output. Templates are executed
+// against the whole config, so "https://{{.host}}:{{.port}}" reads top-level
+// keys and {{.db.host}} a nested one; referring to a missing key is an
+// error. A rendered value may itself be a template, so rendering repeats
+// until nothing changes, at most maxTemplatePasses times. Templates that
+// still render to a template at that point refer to each other in a cycle
+// and are reported as ErrInvalidValue. Nothing is changed unless every
+// template renders.
+func (c *ConfigImpl) RenderTemplates() error {
+	defer c.beginWrite()()
+	root := copyValue(c.tree()).(map[string]interface{})
+	for pass := 0; pass < maxTemplatePasses; pass++ {
+		var pending []string
+		changed := false
+		data := copyValue(root)
+		if err := renderTemplates(root, "", c.separator(), data, &pending, &changed); err != nil {
+			return err
+		}
+		if len(pending) == 0 {
+			return c.commit(root)
+		}
+		if !changed || pass == maxTemplatePasses-1 {
+			sort.Strings(pending)
+			return newPathError(ErrInvalidValue, "config: Template cycle at %q", pending)
+		}
+	}
+	return nil
+}
+
+// renderTemplates renders the string templates in node in place. It records
+// the paths whose output is still a template and whether any value changed.
+func renderTemplates(node interface{}, path, sep string, data interface{}, pending *[]string, changed *bool) error {
+	render := func(v interface{}, path string) (interface{}, error) {
+		s, ok := v.(string)
+		if !ok {
+			return v, renderTemplates(v, path, sep, data, pending, changed)
+		}
+		if !strings.Contains(s, "{{") {
+			return s, nil
+		}
+		tmpl, err := template.New(path).Option("missingkey=error").Parse(s)
+		if err != nil {
+			return nil, newPathError(ErrInvalidValue, "config: Invalid template at %q: %v", path, err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, newPathError(ErrInvalidValue, "config: Cannot render template at %q: %v", path, err)
+		}
+		out := buf.String()
+		if out != s {
+			*changed = true
+		}
+		if strings.Contains(out, "{{") {
+			*pending = append(*pending, path)
+		}
+		return out, nil
+	}
+	switch x := node.(type) {
+	case map[string]interface{}:
+		for k, v := range x {
+			r, err := render(v, joinPath(path, k, sep))
+			if err != nil {
+				return err
+			}
+			x[k] = r
+		}
+	case []interface{}:
+		for i, v := range x {
+			r, err := render(v, joinPath(path, strconv.Itoa(i), sep))
+			if err != nil {
+				return err
+			}
+			x[i] = r
+		}
+	}
+	return nil
+}
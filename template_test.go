@@ -0,0 +1,54 @@
+package config_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigRenderTemplates(t *testing.T) {
+	cfg, err := config.ParseJSON(`{
+		"host": "api.internal",
+		"port": 8443,
+		"base_url": "https://{{.host}}:{{.port}}",
+		"health_url": "{{.base_url}}/healthz",
+		"db": {"host": "db.internal", "dsn": "postgres://{{.db.host}}/app"},
+		"hosts": ["{{.host}}"]
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NoError(t, cfg.RenderTemplates())
+	assert.Equal(t, "https://api.internal:8443", cfg.MustString("base_url"))
+	assert.Equal(t, "https://api.internal:8443/healthz", cfg.MustString("health_url"))
+	assert.Equal(t, "postgres://db.internal/app", cfg.MustString("db.dsn"))
+	assert.Equal(t, "api.internal", cfg.MustString("hosts.0"))
+	assert.Equal(t, 8443, cfg.MustInt("port"))
+}
+
+func Test_ConfigRenderTemplatesErrors(t *testing.T) {
+	cfg, err := config.ParseJSON(`{"a": "{{.b}}", "b": "{{.a}}"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cfg.RenderTemplates()
+	assert.True(t, errors.Is(err, config.ErrInvalidValue))
+	assert.EqualError(t, err, `config: Template cycle at ["a" "b"]`)
+	assert.Equal(t, "{{.b}}", cfg.MustString("a"))
+
+	cfg, err = config.ParseJSON(`{"a": "x{{.a}}"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, errors.Is(cfg.RenderTemplates(), config.ErrInvalidValue))
+
+	cfg, err = config.ParseJSON(`{"url": "{{.missing}}"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, errors.Is(cfg.RenderTemplates(), config.ErrInvalidValue))
+	assert.Equal(t, "{{.missing}}", cfg.MustString("url"))
+}
@@ -0,0 +1,247 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rateUnits maps rate suffixes to the number of seconds they span.
+var rateUnits = []struct {
+	suffix  string
+	seconds float64
+}{
+	{"rps", 1},
+	{"/s", 1},
+	{"/min", 60},
+	{"/h", 3600},
+}
+
+// Rate returns the per-second rate for the dotted path. The value is either a
+// number, taken as events per second, or a string such as "500rps", "2/s",
+// "30/min" or "100/h". Negative rates are errors either way.
+func (c *ConfigImpl) Rate(path string) (float64, error) {
+	x, err := c.Get(path)
+	if err != nil {
+		return -1, err
+	}
+	if f, ok := toFloat64(x); ok {
+		if f < 0 || math.IsNaN(f) {
+			return -1, newPathError(ErrWrongType, "config: Invalid rate %v at %q", f, path)
+		}
+		return f, nil
+	}
+	switch v := x.(type) {
+	case string:
+		s := strings.TrimSpace(v)
+		for _, u := range rateUnits {
+			if !strings.HasSuffix(s, u.suffix) {
+				continue
+			}
+			n, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(s, u.suffix)), 64)
+			if err != nil || n < 0 {
+				break
+			}
+			return n / u.seconds, nil
+		}
+		return -1, newPathError(ErrWrongType, "config: Invalid rate %q at %q", v, path)
+	}
+	return -1, wrongType(x, path)
+}
+
+func (c *ConfigImpl) MustRate(path string, defaults ...float64) float64 {
+	r, err := c.Rate(path)
+	if err == nil && !c.useDefault(path, len(defaults)) {
+		return r
+	}
+	for _, def := range defaults {
+		return def
+	}
+	return float64(missingNumber())
+}
+
+// Percent returns the fraction for the dotted path. The value is either a
+// string such as "10%" or "12.5 %", between "0%" and "100%", or a number
+// already in [0, 1], which is returned as is; "10%" reads as 0.1. Values
+// outside that range fail with ErrInvalidValue.
+func (c *ConfigImpl) Percent(path string) (float64, error) {
+	x, err := c.Get(path)
+	if err != nil {
+		return -1, err
+	}
+	if f, ok := toFloat64(x); ok {
+		if f < 0 || f > 1 || math.IsNaN(f) {
+			return -1, fetchError(ErrInvalidValue, path, path, "config: Fraction %v out of range [0, 1] at %q", f, path)
+		}
+		return f, nil
+	}
+	s, ok := x.(string)
+	if !ok {
+		return -1, wrongType(x, path)
+	}
+	t := strings.TrimSpace(s)
+	if !strings.HasSuffix(t, "%") {
+		return -1, fetchError(ErrWrongType, path, path, "config: Invalid percentage %q at %q", s, path)
+	}
+	n, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(t, "%")), 64)
+	if err != nil {
+		return -1, fetchError(ErrWrongType, path, path, "config: Invalid percentage %q at %q", s, path)
+	}
+	if n < 0 || n > 100 || math.IsNaN(n) {
+		return -1, fetchError(ErrInvalidValue, path, path, "config: Percentage %q out of range [0%%, 100%%] at %q", s, path)
+	}
+	return n / 100, nil
+}
+
+func (c *ConfigImpl) MustPercent(path string, defaults ...float64) float64 {
+	p, err := c.Percent(path)
+	if err == nil && !c.useDefault(path, len(defaults)) {
+		return p
+	}
+	for _, def := range defaults {
+		return def
+	}
+	return 0
+}
+
+// byteUnits maps upper-cased size suffixes to their multiplier. Decimal
+// suffixes are powers of 1000 and binary ones powers of 1024.
+var byteUnits = map[string]float64{
+	"":    1,
+	"B":   1,
+	"KB":  1e3,
+	"MB":  1e6,
+	"GB":  1e9,
+	"TB":  1e12,
+	"PB":  1e15,
+	"KIB": 1 << 10,
+	"MIB": 1 << 20,
+	"GIB": 1 << 30,
+	"TIB": 1 << 40,
+	"PIB": 1 << 50,
+}
+
+// Bytes returns the byte count for the dotted path. The value is either a
+// whole number of bytes or a string such as "10MB", "512KiB" or "1.5GB";
+// suffixes are case-insensitive and may follow a space. Fractions of a byte
+// are truncated. Negative sizes and unknown suffixes are errors.
+func (c *ConfigImpl) Bytes(path string) (int64, error) {
+	x, err := c.Get(path)
+	if err != nil {
+		return -1, err
+	}
+	if s, ok := x.(string); ok {
+		s = strings.TrimSpace(s)
+		split := strings.LastIndexAny(s, "0123456789.") + 1
+		mult, known := byteUnits[strings.ToUpper(strings.TrimSpace(s[split:]))]
+		n, err := strconv.ParseFloat(s[:split], 64)
+		if !known || err != nil || n < 0 || n*mult >= math.MaxInt64 {
+			return -1, newPathError(ErrWrongType, "config: Invalid size %q at %q", s, path)
+		}
+		return int64(n * mult), nil
+	}
+	if n, ok := toInt64(x); ok && n >= 0 {
+		return n, nil
+	}
+	return -1, wrongType(x, path)
+}
+
+func (c *ConfigImpl) MustBytes(path string, defaults ...int64) int64 {
+	b, err := c.Bytes(path)
+	if err == nil && !c.useDefault(path, len(defaults)) {
+		return b
+	}
+	for _, def := range defaults {
+		return def
+	}
+	return missingNumber()
+}
+
+// Duration returns the duration for the dotted path. The value must be a
+// string accepted by time.ParseDuration, such as "1.5s" or "2h45m"; bare
+// numbers are rejected since their unit would be a guess.
+func (c *ConfigImpl) Duration(path string) (time.Duration, error) {
+	x, err := c.Get(path)
+	if err != nil {
+		return -1, err
+	}
+	return parseDuration(x, path)
+}
+
+func (c *ConfigImpl) MustDuration(path string, defaults ...time.Duration) time.Duration {
+	d, err := c.Duration(path)
+	if err == nil && !c.useDefault(path, len(defaults)) {
+		return d
+	}
+	for _, def := range defaults {
+		return def
+	}
+	return 0
+}
+
+// DurationList reads the list at the dotted path as durations, parsing each
+// element like Duration. The error for a malformed element names its index.
+func (c *ConfigImpl) DurationList(path string) ([]time.Duration, error) {
+	l, err := c.List(path)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]time.Duration, len(l))
+	for i, v := range l {
+		if out[i], err = parseDuration(v, joinPath(path, strconv.Itoa(i), c.separator())); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// DurationMap is the map counterpart of DurationList; the error for a
+// malformed value names its key.
+func (c *ConfigImpl) DurationMap(path string) (map[string]time.Duration, error) {
+	m, err := c.Map(path)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]time.Duration, len(m))
+	for k, v := range m {
+		d, err := parseDuration(v, c.childPath(path, k))
+		if err != nil {
+			return nil, err
+		}
+		out[k] = d
+	}
+	return out, nil
+}
+
+func parseDuration(x interface{}, path string) (time.Duration, error) {
+	s, ok := x.(string)
+	if !ok {
+		return -1, wrongType(x, path)
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(s))
+	if err != nil {
+		return -1, fetchError(ErrWrongType, path, path, "config: Invalid duration %q at %q", s, path)
+	}
+	return d, nil
+}
@@ -0,0 +1,148 @@
+package config_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigRate(t *testing.T) {
+	cfg, err := config.ParseJSON(`{
+		"api": "500rps",
+		"login": "2/s",
+		"signup": "30/min",
+		"raw": 12.5,
+		"negative": -5,
+		"negativeString": "-5rps",
+		"bad": "fast"
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := cfg.Rate("api")
+	assert.NoError(t, err)
+	assert.Equal(t, 500.0, r)
+	assert.Equal(t, 2.0, cfg.MustRate("login"))
+	assert.Equal(t, 0.5, cfg.MustRate("signup"))
+	assert.Equal(t, 12.5, cfg.MustRate("raw"))
+
+	_, err = cfg.Rate("bad")
+	assert.Error(t, err)
+	assert.Equal(t, 10.0, cfg.MustRate("bad", 10))
+
+	_, err = cfg.Rate("negative")
+	assert.True(t, errors.Is(err, config.ErrWrongType))
+	assert.EqualError(t, err, `config: Invalid rate -5 at "negative"`)
+	_, err = cfg.Rate("negativeString")
+	assert.True(t, errors.Is(err, config.ErrWrongType))
+}
+
+func Test_ConfigPercent(t *testing.T) {
+	cfg, err := config.ParseJSON(`{
+		"sample_rate": "10%",
+		"rollout": "100%",
+		"spaced": " 12.5 % ",
+		"fraction": 0.25,
+		"whole": 1,
+		"over": "150%",
+		"big": 10,
+		"plain": "0.5"
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := cfg.Percent("sample_rate")
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.10, p, 1e-9)
+	assert.Equal(t, 1.0, cfg.MustPercent("rollout"))
+	assert.InDelta(t, 0.125, cfg.MustPercent("spaced"), 1e-9)
+	assert.Equal(t, 0.25, cfg.MustPercent("fraction"))
+	assert.Equal(t, 1.0, cfg.MustPercent("whole"))
+
+	_, err = cfg.Percent("over")
+	assert.True(t, errors.Is(err, config.ErrInvalidValue))
+	assert.EqualError(t, err, `config: Percentage "150%" out of range [0%, 100%] at "over"`)
+	_, err = cfg.Percent("big")
+	assert.True(t, errors.Is(err, config.ErrInvalidValue))
+	_, err = cfg.Percent("plain")
+	assert.True(t, errors.Is(err, config.ErrWrongType))
+	assert.Equal(t, 0.5, cfg.MustPercent("plain", 0.5))
+}
+
+func Test_ConfigBytes(t *testing.T) {
+	cfg, err := config.ParseJSON(`{
+		"cache": "10MB",
+		"buffer": "512KiB",
+		"disk": "1.5GB",
+		"spaced": "2 mib",
+		"raw": 4096,
+		"rawString": "100",
+		"negative": "-1KB",
+		"unknown": "10XB",
+		"fraction": 1.5
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := cfg.Bytes("cache")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10000000), b)
+	assert.Equal(t, int64(524288), cfg.MustBytes("buffer"))
+	assert.Equal(t, int64(1500000000), cfg.MustBytes("disk"))
+	assert.Equal(t, int64(2097152), cfg.MustBytes("spaced"))
+	assert.Equal(t, int64(4096), cfg.MustBytes("raw"))
+	assert.Equal(t, int64(100), cfg.MustBytes("rawString"))
+
+	for _, path := range []string{"negative", "unknown", "fraction"} {
+		_, err = cfg.Bytes(path)
+		assert.Error(t, err, path)
+	}
+	assert.Equal(t, int64(64), cfg.MustBytes("unknown", 64))
+}
+
+func Test_ConfigDuration(t *testing.T) {
+	cfg, err := config.ParseJSON(`{
+		"ratelimit": {
+			"backoffs": ["1s", "2s", "4s"],
+			"timeouts": {"read": "5s", "write": "10s"},
+			"bad": ["1s", "soon"],
+			"badMap": {"read": "5s", "write": 10},
+			"dotted": {"db.read": "later"},
+			"window": "1m30s"
+		}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 90*time.Second, cfg.MustDuration("ratelimit.window"))
+	assert.Equal(t, time.Minute, cfg.MustDuration("ratelimit.missing", time.Minute))
+
+	l, err := cfg.DurationList("ratelimit.backoffs")
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}, l)
+
+	m, err := cfg.DurationMap("ratelimit.timeouts")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{"read": 5 * time.Second, "write": 10 * time.Second}, m)
+
+	_, err = cfg.DurationList("ratelimit.bad")
+	assert.True(t, errors.Is(err, config.ErrWrongType))
+	assert.EqualError(t, err, `config: Invalid duration "soon" at "ratelimit.bad.1"`)
+
+	_, err = cfg.DurationMap("ratelimit.badMap")
+	var pathErr *config.PathError
+	assert.True(t, errors.As(err, &pathErr))
+	assert.Equal(t, "ratelimit.badMap.write", pathErr.FullPath)
+
+	_, err = cfg.DurationMap("ratelimit.dotted")
+	assert.True(t, errors.As(err, &pathErr))
+	assert.Equal(t, `ratelimit.dotted.db\.read`, pathErr.FullPath)
+	assert.True(t, cfg.Has(pathErr.FullPath))
+}
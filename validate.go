@@ -0,0 +1,239 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Require checks that every path resolves and returns a single error listing
+// all missing paths, one per line, so they can be fixed in one go. Values
+// that are false, zero, empty or null count as present. The error wraps
+// ErrNotFound.
+func (c *ConfigImpl) Require(paths ...string) error {
+	var missing []string
+	for _, path := range paths {
+		if !c.Has(path) {
+			missing = append(missing, path)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return newPathError(ErrNotFound, "config: missing required paths:\n  %s", strings.Join(missing, "\n  "))
+}
+
+// CheckPaths reports for each path whether it resolves, for readiness and
+// health reports that list present keys rather than fail on missing ones.
+// As with Has, a null value counts as present.
+func (c *ConfigImpl) CheckPaths(paths []string) map[string]bool {
+	out := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		out[path] = c.Has(path)
+	}
+	return out
+}
+
+// ValidateInt checks that the path holds an integer within [min, max].
+func (c *ConfigImpl) ValidateInt(path string, min, max int) error {
+	i, err := c.Int(path)
+	if err != nil {
+		return err
+	}
+	if i < min || i > max {
+		return newPathError(ErrInvalidValue, "config: Value %d at %q is outside [%d, %d]", i, path, min, max)
+	}
+	return nil
+}
+
+// ValidateFloat checks that the path holds a number within [min, max].
+func (c *ConfigImpl) ValidateFloat(path string, min, max float64) error {
+	f, err := c.Float(path)
+	if err != nil {
+		return err
+	}
+	if f < min || f > max {
+		return newPathError(ErrInvalidValue, "config: Value %v at %q is outside [%v, %v]", f, path, min, max)
+	}
+	return nil
+}
+
+// ValidateEnum checks that the path holds one of the allowed strings.
+func (c *ConfigImpl) ValidateEnum(path string, allowed ...string) error {
+	s, err := c.String(path)
+	if err != nil {
+		return err
+	}
+	for _, a := range allowed {
+		if s == a {
+			return nil
+		}
+	}
+	return newPathError(ErrInvalidValue, "config: Value %q at %q is not one of %q", s, path, allowed)
+}
+
+// checkBounds applies the WithBounds option.
+func (c *ConfigImpl) checkBounds() error {
+	paths := make([]string, 0, len(c.opts.bounds))
+	for path := range c.opts.bounds {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	var problems []string
+	for _, path := range paths {
+		r := c.opts.bounds[path]
+		if !c.Has(path) {
+			continue
+		}
+		if err := c.ValidateFloat(path, r.Min, r.Max); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return newPathError(ErrInvalidValue, "config: bounds check failed:\n  %s", strings.Join(problems, "\n  "))
+}
+
+// ValidateSchema checks the config against a struct describing its shape.
+// Each field tagged `config:"path"` names a path relative to its parent;
+// `required:"true"` makes the path mandatory; as with Require, a global
+// default does not make a path present. A present value must match the
+// field's Go type: bool, integer (whole numbers only), float, string, slice
+// (a list), map (an object) or time.Duration (a duration string or number).
+// Tagged struct fields must be objects whose own fields are checked beneath
+// them, and untagged struct fields are checked at their parent's level. All
+// problems are returned in one error, one "path: problem" per line.
+func (c *ConfigImpl) ValidateSchema(schema interface{}) error {
+	t := reflect.TypeOf(schema)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return fmt.Errorf("config: ValidateSchema requires a struct, got %T", schema)
+	}
+	var problems []string
+	wrongType := false
+	var check func(t reflect.Type, prefix []string)
+	check = func(t reflect.Type, prefix []string) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag, tagged := f.Tag.Lookup("config")
+			ft := f.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if !tagged || tag == "" || tag == "-" {
+				if ft.Kind() == reflect.Struct && !tagged {
+					check(ft, prefix)
+				}
+				continue
+			}
+			parts := append(append([]string{}, prefix...), c.split(tag)...)
+			path := c.join(parts...)
+			x, err := c.lookup(path)
+			if err != nil {
+				if f.Tag.Get("required") == "true" {
+					problems = append(problems, path+": missing")
+				}
+				continue
+			}
+			if want, ok := schemaTypeMatches(ft, x); !ok {
+				wrongType = true
+				problems = append(problems, fmt.Sprintf("%s: expected %s, got %s", path, want, jsonTypeName(x)))
+				continue
+			}
+			if ft.Kind() == reflect.Struct && ft != durationType {
+				check(ft, parts)
+			}
+		}
+	}
+	check(t, nil)
+	if len(problems) == 0 {
+		return nil
+	}
+	reason := ErrNotFound
+	if wrongType {
+		reason = ErrWrongType
+	}
+	return newPathError(reason, "config: schema validation failed:\n  %s", strings.Join(problems, "\n  "))
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// schemaTypeMatches reports whether x can populate a field of type t, and
+// names the expected JSON type otherwise.
+func schemaTypeMatches(t reflect.Type, x interface{}) (string, bool) {
+	if t == durationType {
+		if s, ok := x.(string); ok {
+			_, err := time.ParseDuration(s)
+			return "duration", err == nil
+		}
+		_, ok := toInt64(x)
+		return "duration", ok
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		_, ok := x.(bool)
+		return "bool", ok
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		_, ok := toInt64(x)
+		return "integer", ok
+	case reflect.Float32, reflect.Float64:
+		_, ok := toFloat64(x)
+		return "number", ok
+	case reflect.String:
+		_, ok := x.(string)
+		return "string", ok
+	case reflect.Slice, reflect.Array:
+		_, ok := x.([]interface{})
+		return "array", ok
+	case reflect.Map, reflect.Struct:
+		_, ok := x.(map[string]interface{})
+		return "object", ok
+	}
+	return "any", true
+}
+
+// jsonTypeName names the JSON type of a decoded value.
+func jsonTypeName(x interface{}) string {
+	switch x.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case string:
+		return "string"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	}
+	if _, ok := toFloat64(x); ok {
+		return "number"
+	}
+	return fmt.Sprintf("%T", x)
+}
@@ -0,0 +1,242 @@
+package config_test
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigRequire(t *testing.T) {
+	cfg, err := config.ParseJSON(`{"debug": false, "workers": 0, "name": "", "proxy": null, "db": {"host": "db"}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.True(t, cfg.Has("proxy"))
+	assert.False(t, cfg.Has("db.port"))
+	assert.NoError(t, cfg.Require("debug", "workers", "name", "proxy", "db.host"))
+
+	err = cfg.Require("debug", "db.port", "db.host", "tls.cert")
+	assert.True(t, errors.Is(err, config.ErrNotFound))
+	assert.Equal(t, "config: missing required paths:\n  db.port\n  tls.cert", err.Error())
+}
+
+func Test_ConfigCollect(t *testing.T) {
+	cfg, err := config.ParseJSON(`{"host": 42, "timeout": 5, "debug": true}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var host string
+	var port, timeout int
+	var debug bool
+	err = cfg.Collect(func(g *config.Getter) {
+		host = g.String("host")
+		port = g.Int("port")
+		timeout = g.Int("timeout")
+		debug = g.Bool("debug")
+	})
+	assert.Equal(t, "", host)
+	assert.Equal(t, 0, port)
+	assert.Equal(t, 5, timeout)
+	assert.True(t, debug)
+
+	var collected *config.CollectError
+	assert.True(t, errors.As(err, &collected))
+	assert.Len(t, collected.Errors, 2)
+	assert.True(t, errors.Is(err, config.ErrWrongType))
+	assert.True(t, errors.Is(err, config.ErrNotFound))
+	assert.Contains(t, err.Error(), "config: 2 values could not be read:")
+
+	assert.NoError(t, cfg.Collect(func(g *config.Getter) {
+		timeout = g.Int("timeout")
+	}))
+}
+
+func Test_ConfigCheckPaths(t *testing.T) {
+	cfg, err := config.ParseJSON(`{"db": {"host": "db", "replica": null}, "hosts": ["a"]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, map[string]bool{
+		"db.host":    true,
+		"db.replica": true,
+		"hosts.0":    true,
+		"hosts.1":    false,
+		"db.port":    false,
+		"tls.cert":   false,
+	}, cfg.CheckPaths([]string{"db.host", "db.replica", "hosts.0", "hosts.1", "db.port", "tls.cert"}))
+	assert.Empty(t, cfg.CheckPaths(nil))
+}
+
+func Test_ConfigValidateSchemaGlobalDefault(t *testing.T) {
+	defer config.ResetGlobalDefaults()
+	config.SetGlobalDefault("server.port", 8080)
+	cfg, err := config.ParseJSON(`{"server": {"host": "db"}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var schema struct {
+		Port int `config:"server.port" required:"true"`
+	}
+	assert.Equal(t, 8080, cfg.MustInt("server.port"))
+	assert.True(t, errors.Is(cfg.Require("server.port"), config.ErrNotFound))
+	err = cfg.ValidateSchema(schema)
+	assert.True(t, errors.Is(err, config.ErrNotFound))
+	assert.EqualError(t, err, "config: schema validation failed:\n  server.port: missing")
+}
+
+func Test_ConfigValidateSchema(t *testing.T) {
+	cfg, err := config.ParseJSONFile("resources/config/default.conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type pants struct {
+		Waist  int     `config:"waist" required:"true"`
+		Height float64 `config:"height"`
+	}
+	type schema struct {
+		Debug   bool     `config:"debug" required:"true"`
+		Name    string   `config:"name" required:"true"`
+		Age     int      `config:"age"`
+		Height  float64  `config:"height"`
+		Hobbies []string `config:"hobbies"`
+		Pants   pants    `config:"clothes.pants"`
+		Ignored string
+	}
+	assert.NoError(t, cfg.ValidateSchema(schema{}))
+	assert.NoError(t, cfg.ValidateSchema(&schema{}))
+
+	type broken struct {
+		Debug   string            `config:"debug"`
+		Age     bool              `config:"age"`
+		Height  int               `config:"height"`
+		Clothes map[string]string `config:"clothes"`
+		Port    int               `config:"server.port" required:"true"`
+		Hobbies map[string]string `config:"hobbies"`
+	}
+	err = cfg.ValidateSchema(broken{})
+	assert.True(t, errors.Is(err, config.ErrWrongType))
+	assert.Equal(t, "config: schema validation failed:\n"+
+		"  debug: expected string, got bool\n"+
+		"  age: expected bool, got number\n"+
+		"  height: expected integer, got number\n"+
+		"  server.port: missing\n"+
+		"  hobbies: expected object, got array", err.Error())
+
+	assert.Error(t, cfg.ValidateSchema("not a struct"))
+}
+
+func Test_ConfigValidateValues(t *testing.T) {
+	cfg, err := config.ParseJSONFile("resources/config/default.conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NoError(t, cfg.ValidateInt("age", 18, 99))
+	err = cfg.ValidateInt("age", 30, 99)
+	assert.True(t, errors.Is(err, config.ErrInvalidValue))
+	assert.Equal(t, `config: Value 26 at "age" is outside [30, 99]`, err.Error())
+	err = cfg.ValidateInt("age", 0, 20)
+	assert.True(t, errors.Is(err, config.ErrInvalidValue))
+	assert.True(t, errors.Is(cfg.ValidateInt("name", 0, 20), config.ErrWrongType))
+
+	assert.NoError(t, cfg.ValidateFloat("height", 4.5, 7))
+	assert.True(t, errors.Is(cfg.ValidateFloat("height", 5.5, 7), config.ErrInvalidValue))
+	assert.True(t, errors.Is(cfg.ValidateFloat("height", 0, 5), config.ErrInvalidValue))
+
+	assert.NoError(t, cfg.ValidateEnum("env", "default", "production"))
+	err = cfg.ValidateEnum("env", "stagging", "production")
+	assert.True(t, errors.Is(err, config.ErrInvalidValue))
+	assert.Equal(t, `config: Value "default" at "env" is not one of ["stagging" "production"]`, err.Error())
+	assert.True(t, errors.Is(cfg.ValidateEnum("missing", "a"), config.ErrNotFound))
+}
+
+func Test_ConfigWithBounds(t *testing.T) {
+	bounds := config.WithBounds(map[string]config.Range{
+		"workers":      {Min: 1, Max: 64},
+		"ratio":        {Min: 0, Max: 1},
+		"server.port":  {Min: 1, Max: 65535},
+		"absent.value": {Min: 0, Max: 1},
+	})
+
+	cfg, err := config.ParseJSON(`{"workers": 8, "ratio": 0.5, "server": {"port": 8080}}`, bounds)
+	assert.NoError(t, err)
+	assert.Equal(t, 8, cfg.MustInt("workers"))
+
+	_, err = config.ParseJSON(`{"workers": 0, "ratio": 1.5, "server": {"port": 8080}}`, bounds)
+	assert.True(t, errors.Is(err, config.ErrInvalidValue))
+	assert.Equal(t, "config: bounds check failed:\n"+
+		"  config: Value 1.5 at \"ratio\" is outside [0, 1]\n"+
+		"  config: Value 0 at \"workers\" is outside [1, 64]", err.Error())
+
+	_, err = config.ParseJSON(`{"workers": 8, "server": {"port": "http"}}`, bounds)
+	assert.Error(t, err)
+}
+
+// requiredValidator understands only the top-level "required" and
+// "properties"/"type" keywords, which is enough to exercise the plumbing.
+var requiredValidator = config.SchemaValidatorFunc(func(schema, document []byte) ([]config.SchemaViolation, error) {
+	var s struct {
+		Required   []string
+		Properties map[string]struct{ Type string }
+	}
+	if err := json.Unmarshal(schema, &s); err != nil {
+		return nil, err
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(document, &doc); err != nil {
+		return nil, err
+	}
+	var out []config.SchemaViolation
+	for _, key := range s.Required {
+		if _, ok := doc[key]; !ok {
+			out = append(out, config.SchemaViolation{Path: "/" + key, Message: "is required"})
+		}
+	}
+	var keys []string
+	for key := range s.Properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		v, ok := doc[key]
+		if _, isNum := v.(float64); ok && s.Properties[key].Type == "number" && !isNum {
+			out = append(out, config.SchemaViolation{Path: "/" + key, Message: "must be a number"})
+		}
+	}
+	return out, nil
+})
+
+func Test_ConfigValidateJSONSchema(t *testing.T) {
+	schema := []byte(`{"required": ["name", "port"], "properties": {"port": {"type": "number"}}}`)
+
+	good, err := config.ParseJSON(`{"name": "api", "port": 8080}`, config.WithSchemaValidator(requiredValidator))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, good.ValidateJSONSchema(schema))
+
+	bad, err := config.ParseJSON(`{"port": "http"}`, config.WithSchemaValidator(requiredValidator))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = bad.ValidateJSONSchema(schema)
+	assert.True(t, errors.Is(err, config.ErrInvalidValue))
+	assert.Equal(t, "config: JSON schema validation failed:\n"+
+		"  /name: is required\n"+
+		"  /port: must be a number", err.Error())
+
+	assert.Error(t, bad.ValidateJSONSchema([]byte(`{`)))
+	plain, err := config.ParseJSON(`{}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Error(t, plain.ValidateJSONSchema(schema))
+}
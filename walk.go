@@ -0,0 +1,102 @@
+// Copyright (c) 2018 Mobentum Labs, LLC.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+)
+
+// SkipSubtree can be returned by a WalkTree callback for a map or list to
+// skip its contents and continue with its siblings, like filepath.SkipDir.
+// Returned for a leaf, by Walk or WalkTree, it is not an error and the walk
+// continues.
+var SkipSubtree = errors.New("config: skip subtree")
+
+// Walk calls fn for every leaf of the config in depth-first order, passing
+// its full path and value. Map keys are visited in sorted order and list
+// elements by index, so "hobbies.0" names the first hobby. Maps and lists are
+// descended into but never passed to fn themselves. Walk stops at and returns
+// the first error fn returns.
+func (c *ConfigImpl) Walk(fn func(path string, value interface{}) error) error {
+	return walk(c.tree(), "", c.separator(), fn)
+}
+
+// WalkTree is Walk that also calls fn for every map and list below the root,
+// before its contents, so that a callback returning SkipSubtree prunes it:
+//
+//	cfg.WalkTree(func(path string, value interface{}) error {
+//		if strings.Contains(path, ".") {
+//			return SkipSubtree // top-level sections only
+//		}
+//		...
+//	})
+func (c *ConfigImpl) WalkTree(fn func(path string, value interface{}) error) error {
+	return walkNodes(c.tree(), "", c.separator(), true, fn)
+}
+
+func walk(node interface{}, path, sep string, fn func(string, interface{}) error) error {
+	return walkNodes(node, path, sep, false, fn)
+}
+
+// walkNodes walks node, passing maps and lists below the root to fn as well
+// as leaves when containers is set.
+func walkNodes(node interface{}, path, sep string, containers bool, fn func(string, interface{}) error) error {
+	node, err := expandDirective(node, path)
+	if err != nil {
+		return err
+	}
+	switch node.(type) {
+	case map[string]interface{}, []interface{}:
+		if containers && path != "" {
+			if err := fn(path, node); err == SkipSubtree {
+				return nil
+			} else if err != nil {
+				return err
+			}
+		}
+	}
+	switch x := node.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if err := walkNodes(x[k], joinPath(path, k, sep), sep, containers, fn); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, e := range x {
+			if err := walkNodes(e, joinPath(path, strconv.Itoa(i), sep), sep, containers, fn); err != nil {
+				return err
+			}
+		}
+	default:
+		if err := fn(path, x); err != SkipSubtree {
+			return err
+		}
+	}
+	return nil
+}
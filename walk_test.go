@@ -0,0 +1,106 @@
+package config_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mobentum/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigWalk(t *testing.T) {
+	cfg, err := config.ParseJSONFile("resources/config/default.conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var paths []string
+	values := map[string]interface{}{}
+	err = cfg.Walk(func(path string, value interface{}) error {
+		paths = append(paths, path)
+		values[path] = value
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"age",
+		"clothes.pants.height",
+		"clothes.pants.waist",
+		"clothes.size",
+		"debug",
+		"env",
+		"height",
+		"hobbies.0",
+		"hobbies.1",
+		"hobbies.2",
+		"hobbies.3",
+		"name",
+		"nested.0",
+		"nested.1.0",
+		"nested.1.1",
+		"nested.1.2.0",
+		"nested.1.2.1",
+		"nested.1.2.2",
+		"nested.1.2.3.0.a",
+		"nested.1.2.3.0.b",
+		"single",
+	}, paths)
+	assert.Equal(t, "c", values["nested.1.2.3.0.b"])
+
+	stop := errors.New("stop")
+	visited := 0
+	err = cfg.Walk(func(path string, value interface{}) error {
+		visited++
+		if path == "debug" {
+			return stop
+		}
+		return nil
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 5, visited)
+}
+
+func Test_ConfigWalkTreeSkipSubtree(t *testing.T) {
+	cfg, err := config.ParseJSONFile("resources/config/default.conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var paths []string
+	err = cfg.WalkTree(func(path string, value interface{}) error {
+		paths = append(paths, path)
+		if path == "nested" || path == "clothes.pants" {
+			return config.SkipSubtree
+		}
+		if path == "age" {
+			return config.SkipSubtree
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"age",
+		"clothes",
+		"clothes.pants",
+		"clothes.size",
+		"debug",
+		"env",
+		"height",
+		"hobbies",
+		"hobbies.0",
+		"hobbies.1",
+		"hobbies.2",
+		"hobbies.3",
+		"name",
+		"nested",
+		"single",
+	}, paths)
+
+	visited := 0
+	err = cfg.Walk(func(path string, value interface{}) error {
+		visited++
+		return config.SkipSubtree
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 21, visited)
+}